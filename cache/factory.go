@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	client "github.com/castai/k8s-client-go"
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+type SharedInformerFactoryOption func(f *SharedInformerFactory)
+
+// WithNamespace limits informers to objects in given namespace.
+func WithNamespace(namespace string) SharedInformerFactoryOption {
+	return func(f *SharedInformerFactory) {
+		f.namespace = namespace
+	}
+}
+
+// WithListOptions sets list options, e.g. label and field selectors, used by all informers.
+func WithListOptions(opts metav1.ListOptions) SharedInformerFactoryOption {
+	return func(f *SharedInformerFactory) {
+		f.listOpts = opts
+	}
+}
+
+// WithObjectAPIOptions sets options for object API used by informers.
+func WithObjectAPIOptions(opts ...client.ObjectAPIOption) SharedInformerFactoryOption {
+	return func(f *SharedInformerFactory) {
+		f.apiOpts = opts
+	}
+}
+
+// WithReflectorOptions sets options for informer reflectors.
+func WithReflectorOptions(opts ...ReflectorOption) SharedInformerFactoryOption {
+	return func(f *SharedInformerFactory) {
+		f.reflectorOpts = opts
+	}
+}
+
+// runnableInformer is a type independent view of SharedInformer.
+type runnableInformer interface {
+	Run(ctx context.Context)
+	HasSynced() bool
+}
+
+// SharedInformerFactory hands out single shared informer per object type identified by its GVR.
+type SharedInformerFactory struct {
+	kc            client.Interface
+	namespace     string
+	listOpts      metav1.ListOptions
+	apiOpts       []client.ObjectAPIOption
+	reflectorOpts []ReflectorOption
+
+	mu        sync.Mutex
+	informers map[metav1.GroupVersionResource]runnableInformer
+	started   map[metav1.GroupVersionResource]bool
+}
+
+// NewSharedInformerFactory creates informer factory. By default, informers watch all namespaces.
+func NewSharedInformerFactory(kc client.Interface, opt ...SharedInformerFactoryOption) *SharedInformerFactory {
+	f := &SharedInformerFactory{
+		kc:        kc,
+		informers: map[metav1.GroupVersionResource]runnableInformer{},
+		started:   map[metav1.GroupVersionResource]bool{},
+	}
+	for _, o := range opt {
+		o(f)
+	}
+	return f
+}
+
+// InformerFor returns shared informer for T, creating it on first call. Informers created after Start
+// are started on the next Start call. Error is returned if informer for the same GVR is already
+// registered with a different type.
+func InformerFor[T corev1.Object](f *SharedInformerFactory) (SharedInformer[T], error) {
+	var t T
+	gvr := t.GVR()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if informer, ok := f.informers[gvr]; ok {
+		typed, ok := informer.(SharedInformer[T])
+		if !ok {
+			return nil, fmt.Errorf("informer for %v is already registered with type %T", gvr, informer)
+		}
+		return typed, nil
+	}
+	informer := NewSharedInformer[T](client.NewObjectAPI[T](f.kc, f.apiOpts...), f.namespace, f.listOpts, f.reflectorOpts...)
+	f.informers[gvr] = informer
+	return informer, nil
+}
+
+// Start starts all requested informers which are not started yet. Informers are stopped when context is done.
+func (f *SharedInformerFactory) Start(ctx context.Context) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for gvr, informer := range f.informers {
+		if f.started[gvr] {
+			continue
+		}
+		f.started[gvr] = true
+		go informer.Run(ctx)
+	}
+}
+
+// WaitForCacheSync waits until caches of all started informers are synced or context is done.
+// Returns sync state for each started informer.
+func (f *SharedInformerFactory) WaitForCacheSync(ctx context.Context) map[metav1.GroupVersionResource]bool {
+	f.mu.Lock()
+	informers := map[metav1.GroupVersionResource]runnableInformer{}
+	for gvr, informer := range f.informers {
+		if f.started[gvr] {
+			informers[gvr] = informer
+		}
+	}
+	f.mu.Unlock()
+
+	res := map[metav1.GroupVersionResource]bool{}
+	for gvr, informer := range informers {
+		res[gvr] = WaitForCacheSync(ctx, informer.HasSynced)
+	}
+	return res
+}
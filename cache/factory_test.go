@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+func TestSharedInformerFactory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/test/endpoints" {
+			t.Errorf("unexpected request path %q", r.URL.Path)
+		}
+		if r.URL.Query().Get("watch") == "1" {
+			// Keep watch open until client disconnects.
+			<-r.Context().Done()
+			return
+		}
+		list := corev1.List[corev1.Endpoints]{
+			ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+			Items:    []corev1.Endpoints{*endpoints("test", "endpoint1", "1")},
+		}
+		if err := json.NewEncoder(w).Encode(list); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	f := NewSharedInformerFactory(&testClient{apiServerURL: srv.URL}, WithNamespace("test"))
+	informer, err := InformerFor[corev1.Endpoints](f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same, err := InformerFor[corev1.Endpoints](f); err != nil || same != informer {
+		t.Fatalf("expected the same informer for the same type, got error %v", err)
+	}
+	if _, err := InformerFor[otherEndpoints](f); err == nil {
+		t.Fatal("expected error for different type with the same GVR")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	f.Start(ctx)
+
+	synced := f.WaitForCacheSync(ctx)
+	if len(synced) != 1 || !synced[corev1.Endpoints{}.GVR()] {
+		t.Fatalf("expected endpoints informer to be synced, got %v", synced)
+	}
+	if _, ok := informer.GetStore().GetByKey("test/endpoint1"); !ok {
+		t.Fatal("expected endpoint1 in the store")
+	}
+}
+
+// otherEndpoints is a different type with endpoints GVR.
+type otherEndpoints struct {
+	corev1.Endpoints
+}
+
+type testClient struct {
+	apiServerURL string
+}
+
+func (c *testClient) Do(req *http.Request) (*http.Response, error) {
+	return http.DefaultClient.Do(req)
+}
+
+func (c *testClient) Token() string {
+	return ""
+}
+
+func (c *testClient) APIServerURL() string {
+	return c.apiServerURL
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"fmt"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+)
+
+const (
+	// NamespaceIndex is the name of the index by object namespace.
+	NamespaceIndex = "namespace"
+)
+
+// IndexFunc computes index values for an object.
+type IndexFunc[T corev1.Object] func(obj *T) []string
+
+// Indexers maps index name to IndexFunc.
+type Indexers[T corev1.Object] map[string]IndexFunc[T]
+
+// Indexer is a Store which maintains user defined indices for fast lookups, e.g. all pods on a node.
+type Indexer[T corev1.Object] interface {
+	Store[T]
+	// Index returns objects which share at least one indexed value with given object.
+	Index(indexName string, obj *T) ([]*T, error)
+	// IndexKeys returns keys of objects with given indexed value.
+	IndexKeys(indexName, indexedValue string) ([]string, error)
+	// ByIndex returns objects with given indexed value.
+	ByIndex(indexName, indexedValue string) ([]*T, error)
+	// ListIndexFuncValues returns all values of given index.
+	ListIndexFuncValues(indexName string) []string
+	// GetIndexers returns registered indexers.
+	GetIndexers() Indexers[T]
+	// AddIndexers adds new indexers and indexes objects already in the store.
+	AddIndexers(newIndexers Indexers[T]) error
+}
+
+// MetaNamespaceIndexFunc indexes objects by namespace.
+func MetaNamespaceIndexFunc[T corev1.Object](obj *T) []string {
+	return []string{(*obj).GetObjectMeta().Namespace}
+}
+
+// LabelIndexFunc returns IndexFunc which indexes objects by value of given label.
+// Objects without the label are not indexed.
+func LabelIndexFunc[T corev1.Object](label string) IndexFunc[T] {
+	return func(obj *T) []string {
+		value, ok := (*obj).GetObjectMeta().Labels[label]
+		if !ok {
+			return nil
+		}
+		return []string{value}
+	}
+}
+
+// OwnerUIDIndexFunc indexes objects by UIDs of their owners.
+func OwnerUIDIndexFunc[T corev1.Object](obj *T) []string {
+	refs := (*obj).GetObjectMeta().OwnerReferences
+	res := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		res = append(res, ref.UID)
+	}
+	return res
+}
+
+func newIndexNotFoundError(indexName string) error {
+	return fmt.Errorf("index with name %s does not exist", indexName)
+}
+
+func newIndexConflictError(indexName string) error {
+	return fmt.Errorf("indexer conflict: %s", indexName)
+}
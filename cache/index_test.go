@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+)
+
+func TestIndexer(t *testing.T) {
+	const byApp = "app"
+	indexer := NewIndexer[corev1.Endpoints](Indexers[corev1.Endpoints]{
+		byApp: LabelIndexFunc[corev1.Endpoints]("app"),
+	})
+
+	e1 := endpoints("ns1", "endpoint1", "1")
+	e1.Labels = map[string]string{"app": "a"}
+	e2 := endpoints("ns2", "endpoint2", "1")
+	e2.Labels = map[string]string{"app": "a"}
+	e3 := endpoints("ns2", "endpoint3", "1")
+	indexer.Add(e1)
+	indexer.Add(e2)
+	indexer.Add(e3)
+
+	keys, err := indexer.IndexKeys(byApp, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertKeys(t, []string{"ns1/endpoint1", "ns2/endpoint2"}, keys)
+
+	// Updated object is moved to the new indexed value.
+	e2Updated := endpoints("ns2", "endpoint2", "2")
+	e2Updated.Labels = map[string]string{"app": "b"}
+	indexer.Update(e2Updated)
+	keys, _ = indexer.IndexKeys(byApp, "a")
+	assertKeys(t, []string{"ns1/endpoint1"}, keys)
+	objs, err := indexer.ByIndex(byApp, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 1 || objs[0].ResourceVersion != "2" {
+		t.Fatalf("unexpected objects %v", objs)
+	}
+
+	indexer.Delete(e1)
+	assertKeys(t, []string{"b"}, indexer.ListIndexFuncValues(byApp))
+
+	// Indexers added later index existing objects.
+	if err := indexer.AddIndexers(Indexers[corev1.Endpoints]{NamespaceIndex: MetaNamespaceIndexFunc[corev1.Endpoints]}); err != nil {
+		t.Fatal(err)
+	}
+	keys, _ = indexer.IndexKeys(NamespaceIndex, "ns2")
+	assertKeys(t, []string{"ns2/endpoint2", "ns2/endpoint3"}, keys)
+
+	if err := indexer.AddIndexers(Indexers[corev1.Endpoints]{byApp: LabelIndexFunc[corev1.Endpoints]("app")}); err == nil {
+		t.Fatal("expected indexer conflict error")
+	}
+	if _, err := indexer.ByIndex("unknown", "a"); err == nil {
+		t.Fatal("expected unknown index error")
+	}
+}
+
+func assertKeys(t *testing.T, expected, actual []string) {
+	t.Helper()
+	if fmt.Sprint(expected) != fmt.Sprint(actual) {
+		t.Fatalf("expected keys %v, got %v", expected, actual)
+	}
+}
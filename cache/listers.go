@@ -0,0 +1,91 @@
+package cache
+
+import (
+	client "github.com/castai/k8s-client-go"
+	"github.com/castai/k8s-client-go/labels"
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+)
+
+// Lister lists objects from the local cache instead of API server.
+type Lister[T corev1.Object] interface {
+	// List returns all objects in the cache matching selector.
+	List(selector labels.Selector) ([]*T, error)
+	// Get returns cluster scoped object by name. Not found error is returned if object is not in the cache.
+	Get(name string) (*T, error)
+	// Namespace returns lister for objects in given namespace.
+	Namespace(namespace string) NamespaceLister[T]
+}
+
+// NamespaceLister lists namespaced objects from the local cache.
+type NamespaceLister[T corev1.Object] interface {
+	// List returns all objects in the namespace matching selector.
+	List(selector labels.Selector) ([]*T, error)
+	// Get returns object by name. Not found error is returned if object is not in the cache.
+	Get(name string) (*T, error)
+}
+
+// NewLister creates lister backed by indexer, usually informer cache.
+func NewLister[T corev1.Object](indexer Indexer[T]) Lister[T] {
+	return &lister[T]{indexer: indexer}
+}
+
+type lister[T corev1.Object] struct {
+	indexer Indexer[T]
+}
+
+func (l *lister[T]) List(selector labels.Selector) ([]*T, error) {
+	return filterBySelector(l.indexer.List(), selector), nil
+}
+
+func (l *lister[T]) Get(name string) (*T, error) {
+	return getByKey(l.indexer, name, name)
+}
+
+func (l *lister[T]) Namespace(namespace string) NamespaceLister[T] {
+	return &namespaceLister[T]{indexer: l.indexer, namespace: namespace}
+}
+
+type namespaceLister[T corev1.Object] struct {
+	indexer   Indexer[T]
+	namespace string
+}
+
+func (l *namespaceLister[T]) List(selector labels.Selector) ([]*T, error) {
+	objs, err := l.indexer.ByIndex(NamespaceIndex, l.namespace)
+	if err != nil {
+		// Fallback to full scan if namespace index is not registered.
+		objs = nil
+		for _, obj := range l.indexer.List() {
+			if (*obj).GetObjectMeta().Namespace == l.namespace {
+				objs = append(objs, obj)
+			}
+		}
+	}
+	return filterBySelector(objs, selector), nil
+}
+
+func (l *namespaceLister[T]) Get(name string) (*T, error) {
+	return getByKey(l.indexer, l.namespace+"/"+name, name)
+}
+
+func getByKey[T corev1.Object](indexer Indexer[T], key, name string) (*T, error) {
+	obj, ok := indexer.GetByKey(key)
+	if !ok {
+		var t T
+		return nil, client.NewNotFound(t.GVR(), name)
+	}
+	return obj, nil
+}
+
+func filterBySelector[T corev1.Object](objs []*T, selector labels.Selector) []*T {
+	if selector == nil || selector.Empty() {
+		return objs
+	}
+	res := make([]*T, 0, len(objs))
+	for _, obj := range objs {
+		if selector.Matches(labels.Set((*obj).GetObjectMeta().Labels)) {
+			res = append(res, obj)
+		}
+	}
+	return res
+}
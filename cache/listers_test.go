@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"testing"
+
+	client "github.com/castai/k8s-client-go"
+	"github.com/castai/k8s-client-go/labels"
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+)
+
+func TestLister(t *testing.T) {
+	indexer := NewIndexer[corev1.Endpoints](Indexers[corev1.Endpoints]{NamespaceIndex: MetaNamespaceIndexFunc[corev1.Endpoints]})
+	e1 := endpoints("ns1", "endpoint1", "1")
+	e1.Labels = map[string]string{"app": "a"}
+	e2 := endpoints("ns1", "endpoint2", "1")
+	e3 := endpoints("ns2", "endpoint3", "1")
+	e3.Labels = map[string]string{"app": "a"}
+	indexer.Add(e1)
+	indexer.Add(e2)
+	indexer.Add(e3)
+
+	lister := NewLister[corev1.Endpoints](indexer)
+
+	all, err := lister.List(labels.Everything())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 objects, got %d", len(all))
+	}
+
+	selected, err := lister.Namespace("ns1").List(labels.SelectorFromSet(labels.Set{"app": "a"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 1 || selected[0].Name != "endpoint1" {
+		t.Fatalf("unexpected selected objects %v", selected)
+	}
+
+	obj, err := lister.Namespace("ns2").Get("endpoint3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj != e3 {
+		t.Fatalf("unexpected object %v", obj)
+	}
+
+	if _, err := lister.Namespace("ns2").Get("endpoint1"); !client.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	client "github.com/castai/k8s-client-go"
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+// ListerWatcher can list and watch objects. ObjectAPI implements it.
+type ListerWatcher[T corev1.Object] interface {
+	client.ObjectLister[T]
+	client.ObjectWatcher[T]
+}
+
+type ReflectorOption func(opts *reflectorOptions)
+type reflectorOptions struct {
+	log            client.Logger
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// WithLogger sets logger used to report list and watch failures.
+func WithLogger(log client.Logger) ReflectorOption {
+	return func(opts *reflectorOptions) {
+		opts.log = log
+	}
+}
+
+// WithBackoff sets initial and max delay between failed list and watch attempts.
+func WithBackoff(initial, maxDelay time.Duration) ReflectorOption {
+	return func(opts *reflectorOptions) {
+		opts.initialBackoff = initial
+		opts.maxBackoff = maxDelay
+	}
+}
+
+// Reflector keeps store in sync with API server. It lists all objects, replaces store content and
+// watches for changes starting from the list resource version. When resource version expires
+// objects are listed again.
+type Reflector[T corev1.Object] struct {
+	lw        ListerWatcher[T]
+	namespace string
+	opts      metav1.ListOptions
+	store     Store[T]
+	ropts     reflectorOptions
+
+	mu                      sync.RWMutex
+	lastSyncResourceVersion string
+}
+
+// NewReflector creates reflector for objects in given namespace, empty namespace means all namespaces.
+// Label and field selectors and page size are taken from opts.
+func NewReflector[T corev1.Object](lw ListerWatcher[T], namespace string, opts metav1.ListOptions, store Store[T], opt ...ReflectorOption) *Reflector[T] {
+	ropts := reflectorOptions{
+		log:            &client.DefaultLogger{},
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     30 * time.Second,
+	}
+	for _, o := range opt {
+		o(&ropts)
+	}
+	return &Reflector[T]{
+		lw:        lw,
+		namespace: namespace,
+		opts:      opts,
+		store:     store,
+		ropts:     ropts,
+	}
+}
+
+// LastSyncResourceVersion returns resource version of the latest observed list or watch event.
+func (r *Reflector[T]) LastSyncResourceVersion() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lastSyncResourceVersion
+}
+
+func (r *Reflector[T]) setLastSyncResourceVersion(rv string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastSyncResourceVersion = rv
+}
+
+// Run repeatedly lists and watches objects until context is done. Failed attempts and relists after
+// expired resource version are delayed with backoff, which is reset once watch delivers events.
+func (r *Reflector[T]) Run(ctx context.Context) {
+	backoff := r.ropts.initialBackoff
+	for {
+		watched, err := r.listAndWatch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if watched {
+			backoff = r.ropts.initialBackoff
+		}
+		if !client.IsGone(err) {
+			r.ropts.log.Infof("k8s-client-go: reflector list and watch failed: %v", err)
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > r.ropts.maxBackoff {
+			backoff = r.ropts.maxBackoff
+		}
+	}
+}
+
+// ListAndWatch lists all objects, replaces store content and watches for changes until resource
+// version expires, context is done or watch fails.
+func (r *Reflector[T]) ListAndWatch(ctx context.Context) error {
+	_, err := r.listAndWatch(ctx)
+	return err
+}
+
+// listAndWatch is ListAndWatch which also reports whether watch delivered any events.
+func (r *Reflector[T]) listAndWatch(ctx context.Context) (bool, error) {
+	listOpts := r.opts
+	listOpts.ResourceVersion = ""
+	listOpts.ResourceVersionMatch = ""
+	listOpts.Continue = ""
+	list, err := client.ListAll[T](ctx, r.lw, r.namespace, listOpts)
+	if err != nil {
+		return false, fmt.Errorf("listing objects: %w", err)
+	}
+	items := make([]*T, len(list.Items))
+	for i := range list.Items {
+		items[i] = &list.Items[i]
+	}
+	r.store.Replace(items, list.ResourceVersion)
+	r.setLastSyncResourceVersion(list.ResourceVersion)
+
+	watchOpts := r.opts
+	watchOpts.ResourceVersion = list.ResourceVersion
+	watchOpts.ResourceVersionMatch = ""
+	watchOpts.Limit = 0
+	watchOpts.Continue = ""
+	w, err := client.NewRetryWatcher[T](ctx, r.lw, r.namespace, "", watchOpts, client.WithRetryLogger(r.ropts.log), client.WithRetryBackoff(r.ropts.initialBackoff, r.ropts.maxBackoff))
+	if err != nil {
+		return false, fmt.Errorf("watching objects: %w", err)
+	}
+	defer w.Stop()
+
+	watched := false
+	for e := range w.ResultChan() {
+		switch e.Type {
+		case corev1.EventTypeAdded:
+			r.store.Add(e.Object)
+		case corev1.EventTypeModified:
+			r.store.Update(e.Object)
+		case corev1.EventTypeDeleted:
+			r.store.Delete(e.Object)
+		case corev1.EventTypeError:
+			continue
+		}
+		watched = true
+		r.setLastSyncResourceVersion(w.ResourceVersion())
+	}
+	if err := w.Err(); err != nil {
+		return watched, err
+	}
+	return watched, ctx.Err()
+}
@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	client "github.com/castai/k8s-client-go"
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+func TestReflector(t *testing.T) {
+	lw := newFakeListerWatcher()
+	lw.setList("10", endpoints("test", "endpoint1", "9"), endpoints("test", "endpoint2", "10"))
+
+	store := NewStore[corev1.Endpoints]()
+	r := NewReflector[corev1.Endpoints](lw, "test", metav1.ListOptions{}, store, WithBackoff(time.Millisecond, 10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	waitFor(t, func() bool { return len(store.ListKeys()) == 2 })
+
+	w := lw.nextWatch(t)
+	if w.opts.ResourceVersion != "10" {
+		t.Fatalf("expected watch from resource version %q, got %q", "10", w.opts.ResourceVersion)
+	}
+	w.send(corev1.Event[corev1.Endpoints]{Type: corev1.EventTypeDeleted, Object: endpoints("test", "endpoint1", "11")})
+	w.send(corev1.Event[corev1.Endpoints]{Type: corev1.EventTypeAdded, Object: endpoints("test", "endpoint3", "12")})
+	waitFor(t, func() bool { return r.LastSyncResourceVersion() == "12" })
+
+	keys := store.ListKeys()
+	if len(keys) != 2 || keys[0] != "test/endpoint2" || keys[1] != "test/endpoint3" {
+		t.Fatalf("unexpected store keys %v", keys)
+	}
+
+	// Expired resource version causes relist.
+	lw.setList("20", endpoints("test", "endpoint4", "20"))
+	w.send(corev1.Event[corev1.Endpoints]{Type: corev1.EventTypeError, Status: &metav1.Status{Code: 410, Reason: metav1.StatusReasonExpired}})
+	waitFor(t, func() bool { return r.LastSyncResourceVersion() == "20" })
+
+	keys = store.ListKeys()
+	if len(keys) != 1 || keys[0] != "test/endpoint4" {
+		t.Fatalf("unexpected store keys after relist %v", keys)
+	}
+}
+
+func TestReflectorExpiredResourceVersionBackoff(t *testing.T) {
+	lw := newFakeListerWatcher()
+	lw.setList("10", endpoints("test", "endpoint1", "10"))
+
+	store := NewStore[corev1.Endpoints]()
+	r := NewReflector[corev1.Endpoints](lw, "test", metav1.ListOptions{}, store, WithBackoff(50*time.Millisecond, time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	// Watch expires right away, so relists are delayed with growing backoff.
+	var started []time.Time
+	for i := 0; i < 3; i++ {
+		w := lw.nextWatch(t)
+		started = append(started, time.Now())
+		w.send(corev1.Event[corev1.Endpoints]{Type: corev1.EventTypeError, Status: &metav1.Status{Code: 410, Reason: metav1.StatusReasonExpired}})
+	}
+	if d := started[1].Sub(started[0]); d < 50*time.Millisecond {
+		t.Fatalf("expected relist after at least 50ms, got %v", d)
+	}
+	if d := started[2].Sub(started[1]); d < 100*time.Millisecond {
+		t.Fatalf("expected relist after at least 100ms, got %v", d)
+	}
+}
+
+func endpoints(namespace, name, resourceVersion string) *corev1.Endpoints {
+	return &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       namespace,
+			Name:            name,
+			ResourceVersion: resourceVersion,
+		},
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type fakeListerWatcher struct {
+	mu      sync.Mutex
+	list    corev1.List[corev1.Endpoints]
+	watches chan *fakeWatcher
+}
+
+func newFakeListerWatcher() *fakeListerWatcher {
+	return &fakeListerWatcher{
+		watches: make(chan *fakeWatcher, 10),
+	}
+}
+
+func (f *fakeListerWatcher) setList(resourceVersion string, items ...*corev1.Endpoints) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.list = corev1.List[corev1.Endpoints]{ListMeta: metav1.ListMeta{ResourceVersion: resourceVersion}}
+	for _, item := range items {
+		f.list.Items = append(f.list.Items, *item)
+	}
+}
+
+func (f *fakeListerWatcher) List(_ context.Context, _ string, _ metav1.ListOptions) (*corev1.List[corev1.Endpoints], error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	list := f.list
+	list.Items = append([]corev1.Endpoints(nil), f.list.Items...)
+	return &list, nil
+}
+
+func (f *fakeListerWatcher) Watch(_ context.Context, _, _ string, opts metav1.ListOptions) (client.WatchInterface[corev1.Endpoints], error) {
+	w := &fakeWatcher{
+		opts:   opts,
+		result: make(chan corev1.Event[corev1.Endpoints]),
+		done:   make(chan struct{}),
+	}
+	f.watches <- w
+	return w, nil
+}
+
+func (f *fakeListerWatcher) nextWatch(t *testing.T) *fakeWatcher {
+	t.Helper()
+	select {
+	case w := <-f.watches:
+		return w
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for watch")
+		return nil
+	}
+}
+
+type fakeWatcher struct {
+	opts     metav1.ListOptions
+	result   chan corev1.Event[corev1.Endpoints]
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func (w *fakeWatcher) send(e corev1.Event[corev1.Endpoints]) {
+	select {
+	case w.result <- e:
+	case <-w.done:
+	}
+}
+
+func (w *fakeWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+	})
+}
+
+func (w *fakeWatcher) ResultChan() <-chan corev1.Event[corev1.Endpoints] {
+	return w.result
+}
+
+func (w *fakeWatcher) ResourceVersion() string {
+	return ""
+}
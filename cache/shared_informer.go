@@ -0,0 +1,327 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+// ResourceEventHandler handles notifications for events that happen to objects.
+type ResourceEventHandler[T corev1.Object] interface {
+	// OnAdd is called when object is added to the cache, including objects from the initial list.
+	OnAdd(obj *T)
+	// OnUpdate is called when object is modified. Both old and new objects are provided.
+	OnUpdate(oldObj, newObj *T)
+	// OnDelete is called when object is deleted. If deletion was missed while watch was disconnected
+	// and object disappeared from relist, finalStateUnknown is true and obj is the last known state.
+	OnDelete(obj *T, finalStateUnknown bool)
+}
+
+// ResourceEventHandlerFuncs is an adaptor to let you easily specify as many or as few of the
+// notification functions as you want while still implementing ResourceEventHandler.
+type ResourceEventHandlerFuncs[T corev1.Object] struct {
+	AddFunc    func(obj *T)
+	UpdateFunc func(oldObj, newObj *T)
+	DeleteFunc func(obj *T, finalStateUnknown bool)
+}
+
+// OnAdd calls AddFunc if it's not nil.
+func (r ResourceEventHandlerFuncs[T]) OnAdd(obj *T) {
+	if r.AddFunc != nil {
+		r.AddFunc(obj)
+	}
+}
+
+// OnUpdate calls UpdateFunc if it's not nil.
+func (r ResourceEventHandlerFuncs[T]) OnUpdate(oldObj, newObj *T) {
+	if r.UpdateFunc != nil {
+		r.UpdateFunc(oldObj, newObj)
+	}
+}
+
+// OnDelete calls DeleteFunc if it's not nil.
+func (r ResourceEventHandlerFuncs[T]) OnDelete(obj *T, finalStateUnknown bool) {
+	if r.DeleteFunc != nil {
+		r.DeleteFunc(obj, finalStateUnknown)
+	}
+}
+
+// SharedInformer keeps local cache of objects using single list and watch connection and
+// notifies all registered handlers about changes.
+type SharedInformer[T corev1.Object] interface {
+	// AddEventHandler registers handler. Handlers added after informer is started receive
+	// OnAdd notifications for all objects already in the cache.
+	AddEventHandler(handler ResourceEventHandler[T])
+	// GetStore returns informer local cache.
+	GetStore() Store[T]
+	// GetIndexer returns informer local cache with indices.
+	GetIndexer() Indexer[T]
+	// AddIndexers adds indexers to informer local cache.
+	AddIndexers(indexers Indexers[T]) error
+	// HasSynced returns true once initial list is stored in the cache.
+	HasSynced() bool
+	// LastSyncResourceVersion returns resource version of the latest observed list or watch event.
+	LastSyncResourceVersion() string
+	// Run starts informer and blocks until context is done. Informer can be run only once.
+	Run(ctx context.Context)
+}
+
+// NewSharedInformer creates informer for objects in given namespace matching opts selectors.
+func NewSharedInformer[T corev1.Object](lw ListerWatcher[T], namespace string, opts metav1.ListOptions, opt ...ReflectorOption) SharedInformer[T] {
+	s := &sharedInformer[T]{
+		store:     NewIndexer[T](Indexers[T]{NamespaceIndex: MetaNamespaceIndexFunc[T]}),
+		processor: &processor[T]{},
+	}
+	s.reflector = NewReflector[T](lw, namespace, opts, &notifyingStore[T]{Store: s.store, processor: s.processor}, opt...)
+	return s
+}
+
+type sharedInformer[T corev1.Object] struct {
+	store     Indexer[T]
+	processor *processor[T]
+	reflector *Reflector[T]
+
+	mu      sync.Mutex
+	started bool
+}
+
+func (s *sharedInformer[T]) AddEventHandler(handler ResourceEventHandler[T]) {
+	s.processor.addListener(handler, s.store)
+}
+
+func (s *sharedInformer[T]) GetStore() Store[T] {
+	return s.store
+}
+
+func (s *sharedInformer[T]) GetIndexer() Indexer[T] {
+	return s.store
+}
+
+func (s *sharedInformer[T]) AddIndexers(indexers Indexers[T]) error {
+	return s.store.AddIndexers(indexers)
+}
+
+func (s *sharedInformer[T]) HasSynced() bool {
+	return s.processor.hasSynced()
+}
+
+func (s *sharedInformer[T]) LastSyncResourceVersion() string {
+	return s.reflector.LastSyncResourceVersion()
+}
+
+func (s *sharedInformer[T]) Run(ctx context.Context) {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	s.processor.run()
+	defer s.processor.stop()
+	s.reflector.Run(ctx)
+}
+
+// WaitForCacheSync waits until all cacheSyncs return true or context is done. Returns false if
+// context is done before caches are synced.
+func WaitForCacheSync(ctx context.Context, cacheSyncs ...func() bool) bool {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		synced := true
+		for _, hasSynced := range cacheSyncs {
+			if !hasSynced() {
+				synced = false
+				break
+			}
+		}
+		if synced {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// notifyingStore updates underlying store and distributes notifications to informer handlers.
+type notifyingStore[T corev1.Object] struct {
+	Store[T]
+	processor *processor[T]
+}
+
+func (n *notifyingStore[T]) Add(obj *T) {
+	n.processor.mu.Lock()
+	defer n.processor.mu.Unlock()
+	old, exists := n.Store.Get(obj)
+	n.Store.Add(obj)
+	if exists {
+		n.processor.distribute(notification[T]{oldObj: old, newObj: obj})
+		return
+	}
+	n.processor.distribute(notification[T]{newObj: obj})
+}
+
+func (n *notifyingStore[T]) Update(obj *T) {
+	n.Add(obj)
+}
+
+func (n *notifyingStore[T]) Delete(obj *T) {
+	n.processor.mu.Lock()
+	defer n.processor.mu.Unlock()
+	n.Store.Delete(obj)
+	n.processor.distribute(notification[T]{oldObj: obj, deleted: true})
+}
+
+func (n *notifyingStore[T]) Replace(objs []*T, resourceVersion string) {
+	n.processor.mu.Lock()
+	defer n.processor.mu.Unlock()
+
+	oldObjs := map[string]*T{}
+	for _, obj := range n.Store.List() {
+		oldObjs[ObjectKey(obj)] = obj
+	}
+	n.Store.Replace(objs, resourceVersion)
+
+	for _, obj := range objs {
+		key := ObjectKey(obj)
+		old, exists := oldObjs[key]
+		delete(oldObjs, key)
+		if !exists {
+			n.processor.distribute(notification[T]{newObj: obj})
+			continue
+		}
+		if (*old).GetObjectMeta().ResourceVersion != (*obj).GetObjectMeta().ResourceVersion {
+			n.processor.distribute(notification[T]{oldObj: old, newObj: obj})
+		}
+	}
+	// Objects which are not in the new list were deleted while watch was not running.
+	for _, obj := range oldObjs {
+		n.processor.distribute(notification[T]{oldObj: obj, deleted: true, finalStateUnknown: true})
+	}
+	n.processor.synced = true
+}
+
+type notification[T corev1.Object] struct {
+	oldObj            *T
+	newObj            *T
+	deleted           bool
+	finalStateUnknown bool
+}
+
+// processor distributes notifications to all registered listeners.
+type processor[T corev1.Object] struct {
+	mu        sync.Mutex
+	listeners []*listener[T]
+	running   bool
+	synced    bool
+}
+
+func (p *processor[T]) addListener(handler ResourceEventHandler[T], store Store[T]) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	l := newListener(handler)
+	p.listeners = append(p.listeners, l)
+	if p.running {
+		l.run()
+	}
+	for _, obj := range store.List() {
+		l.add(notification[T]{newObj: obj})
+	}
+}
+
+// distribute must be called with processor lock held.
+func (p *processor[T]) distribute(n notification[T]) {
+	for _, l := range p.listeners {
+		l.add(n)
+	}
+}
+
+func (p *processor[T]) hasSynced() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.synced
+}
+
+func (p *processor[T]) run() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running = true
+	for _, l := range p.listeners {
+		l.run()
+	}
+}
+
+func (p *processor[T]) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running = false
+	for _, l := range p.listeners {
+		l.stop()
+	}
+	p.listeners = nil
+}
+
+// listener buffers notifications without limit so slow handler doesn't block other handlers.
+type listener[T corev1.Object] struct {
+	handler ResourceEventHandler[T]
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []notification[T]
+	stopped bool
+}
+
+func newListener[T corev1.Object](handler ResourceEventHandler[T]) *listener[T] {
+	l := &listener[T]{handler: handler}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *listener[T]) add(n notification[T]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = append(l.pending, n)
+	l.cond.Signal()
+}
+
+func (l *listener[T]) stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stopped = true
+	l.cond.Signal()
+}
+
+func (l *listener[T]) run() {
+	go func() {
+		for {
+			l.mu.Lock()
+			for len(l.pending) == 0 && !l.stopped {
+				l.cond.Wait()
+			}
+			if l.stopped {
+				l.mu.Unlock()
+				return
+			}
+			n := l.pending[0]
+			l.pending[0] = notification[T]{}
+			l.pending = l.pending[1:]
+			l.mu.Unlock()
+
+			switch {
+			case n.deleted:
+				l.handler.OnDelete(n.oldObj, n.finalStateUnknown)
+			case n.oldObj != nil:
+				l.handler.OnUpdate(n.oldObj, n.newObj)
+			default:
+				l.handler.OnAdd(n.newObj)
+			}
+		}
+	}()
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+func TestSharedInformer(t *testing.T) {
+	lw := newFakeListerWatcher()
+	lw.setList("10", endpoints("test", "endpoint1", "9"), endpoints("test", "endpoint2", "10"))
+
+	informer := NewSharedInformer[corev1.Endpoints](lw, "test", metav1.ListOptions{}, WithBackoff(time.Millisecond, 10*time.Millisecond))
+	h1 := &recordingHandler{}
+	informer.AddEventHandler(h1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go informer.Run(ctx)
+
+	if !WaitForCacheSync(ctx, informer.HasSynced) {
+		t.Fatal("cache not synced")
+	}
+
+	w := lw.nextWatch(t)
+	w.send(corev1.Event[corev1.Endpoints]{Type: corev1.EventTypeModified, Object: endpoints("test", "endpoint1", "11")})
+	w.send(corev1.Event[corev1.Endpoints]{Type: corev1.EventTypeDeleted, Object: endpoints("test", "endpoint2", "12")})
+	waitFor(t, func() bool { return informer.LastSyncResourceVersion() == "12" })
+
+	// Late handler receives current state.
+	h2 := &recordingHandler{}
+	informer.AddEventHandler(h2)
+
+	// Relist misses deletion of endpoint1.
+	lw.setList("20", endpoints("test", "endpoint3", "20"))
+	w.send(corev1.Event[corev1.Endpoints]{Type: corev1.EventTypeError, Status: &metav1.Status{Code: 410, Reason: metav1.StatusReasonExpired}})
+
+	expected1 := []string{
+		"add test/endpoint1",
+		"add test/endpoint2",
+		"update test/endpoint1 9->11",
+		"delete test/endpoint2",
+		"add test/endpoint3",
+		"tombstone test/endpoint1",
+	}
+	expected2 := []string{
+		"add test/endpoint1",
+		"add test/endpoint3",
+		"tombstone test/endpoint1",
+	}
+	waitFor(t, func() bool { return len(h1.get()) == len(expected1) && len(h2.get()) == len(expected2) })
+	assertEvents(t, expected1, h1.get())
+	assertEvents(t, expected2, h2.get())
+}
+
+func assertEvents(t *testing.T, expected, actual []string) {
+	t.Helper()
+	if fmt.Sprint(expected) != fmt.Sprint(actual) {
+		t.Fatalf("expected events %v, got %v", expected, actual)
+	}
+}
+
+type recordingHandler struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (h *recordingHandler) record(format string, args ...any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, fmt.Sprintf(format, args...))
+}
+
+func (h *recordingHandler) get() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.events...)
+}
+
+func (h *recordingHandler) OnAdd(obj *corev1.Endpoints) {
+	h.record("add %s", ObjectKey(obj))
+}
+
+func (h *recordingHandler) OnUpdate(oldObj, newObj *corev1.Endpoints) {
+	h.record("update %s %s->%s", ObjectKey(newObj), oldObj.ResourceVersion, newObj.ResourceVersion)
+}
+
+func (h *recordingHandler) OnDelete(obj *corev1.Endpoints, finalStateUnknown bool) {
+	if finalStateUnknown {
+		h.record("tombstone %s", ObjectKey(obj))
+		return
+	}
+	h.record("delete %s", ObjectKey(obj))
+}
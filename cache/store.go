@@ -0,0 +1,274 @@
+package cache
+
+import (
+	"sort"
+	"sync"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+// Store is a generic thread-safe object storage keyed by namespace/name.
+type Store[T corev1.Object] interface {
+	// Add inserts object into the store.
+	Add(obj *T)
+	// Update updates object in the store.
+	Update(obj *T)
+	// Delete removes object from the store.
+	Delete(obj *T)
+	// List returns all objects in the store.
+	List() []*T
+	// ListKeys returns keys of all objects in the store.
+	ListKeys() []string
+	// Get returns object with the same key as given object.
+	Get(obj *T) (*T, bool)
+	// GetByKey returns object by its key.
+	GetByKey(key string) (*T, bool)
+	// Replace deletes store content and replaces it with given objects. Resource version is the
+	// version of the list objects were taken from.
+	Replace(objs []*T, resourceVersion string)
+}
+
+// MetaNamespaceKeyFunc returns object key in <namespace>/<name> format, or only <name> for
+// cluster scoped objects.
+func MetaNamespaceKeyFunc(meta metav1.ObjectMeta) string {
+	if meta.Namespace == "" {
+		return meta.Name
+	}
+	return meta.Namespace + "/" + meta.Name
+}
+
+// ObjectKey returns store key for given object.
+func ObjectKey[T corev1.Object](obj *T) string {
+	return MetaNamespaceKeyFunc((*obj).GetObjectMeta())
+}
+
+// NewStore creates empty thread-safe store.
+func NewStore[T corev1.Object]() Store[T] {
+	return NewIndexer[T](nil)
+}
+
+// NewIndexer creates empty thread-safe store with given indexers.
+func NewIndexer[T corev1.Object](indexers Indexers[T]) Indexer[T] {
+	s := &store[T]{
+		items:    map[string]*T{},
+		indexers: Indexers[T]{},
+		indices:  map[string]index{},
+	}
+	for name, indexFunc := range indexers {
+		s.indexers[name] = indexFunc
+		s.indices[name] = index{}
+	}
+	return s
+}
+
+// index maps indexed value to a set of object keys.
+type index map[string]map[string]struct{}
+
+type store[T corev1.Object] struct {
+	mu       sync.RWMutex
+	items    map[string]*T
+	indexers Indexers[T]
+	indices  map[string]index
+}
+
+func (s *store[T]) Add(obj *T) {
+	key := ObjectKey(obj)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := s.items[key]
+	s.items[key] = obj
+	s.updateIndices(old, obj, key)
+}
+
+func (s *store[T]) Update(obj *T) {
+	s.Add(obj)
+}
+
+func (s *store[T]) Delete(obj *T) {
+	key := ObjectKey(obj)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if old, ok := s.items[key]; ok {
+		s.updateIndices(old, nil, key)
+		delete(s.items, key)
+	}
+}
+
+func (s *store[T]) List() []*T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]*T, 0, len(s.items))
+	for _, obj := range s.items {
+		res = append(res, obj)
+	}
+	return res
+}
+
+func (s *store[T]) ListKeys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]string, 0, len(s.items))
+	for key := range s.items {
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (s *store[T]) Get(obj *T) (*T, bool) {
+	return s.GetByKey(ObjectKey(obj))
+}
+
+func (s *store[T]) GetByKey(key string) (*T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obj, ok := s.items[key]
+	return obj, ok
+}
+
+func (s *store[T]) Replace(objs []*T, _ string) {
+	items := make(map[string]*T, len(objs))
+	for _, obj := range objs {
+		items[ObjectKey(obj)] = obj
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = items
+	for name := range s.indices {
+		s.indices[name] = index{}
+	}
+	for key, obj := range s.items {
+		s.updateIndices(nil, obj, key)
+	}
+}
+
+func (s *store[T]) Index(indexName string, obj *T) ([]*T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	indexFunc, ok := s.indexers[indexName]
+	if !ok {
+		return nil, newIndexNotFoundError(indexName)
+	}
+	idx := s.indices[indexName]
+	keys := map[string]struct{}{}
+	for _, value := range indexFunc(obj) {
+		for key := range idx[value] {
+			keys[key] = struct{}{}
+		}
+	}
+	res := make([]*T, 0, len(keys))
+	for key := range keys {
+		res = append(res, s.items[key])
+	}
+	return res, nil
+}
+
+func (s *store[T]) IndexKeys(indexName, indexedValue string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	idx, ok := s.indices[indexName]
+	if !ok {
+		return nil, newIndexNotFoundError(indexName)
+	}
+	res := make([]string, 0, len(idx[indexedValue]))
+	for key := range idx[indexedValue] {
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+func (s *store[T]) ByIndex(indexName, indexedValue string) ([]*T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	idx, ok := s.indices[indexName]
+	if !ok {
+		return nil, newIndexNotFoundError(indexName)
+	}
+	keys := idx[indexedValue]
+	res := make([]*T, 0, len(keys))
+	for key := range keys {
+		res = append(res, s.items[key])
+	}
+	return res, nil
+}
+
+func (s *store[T]) ListIndexFuncValues(indexName string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	idx := s.indices[indexName]
+	res := make([]string, 0, len(idx))
+	for value := range idx {
+		res = append(res, value)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (s *store[T]) GetIndexers() Indexers[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make(Indexers[T], len(s.indexers))
+	for name, indexFunc := range s.indexers {
+		res[name] = indexFunc
+	}
+	return res
+}
+
+func (s *store[T]) AddIndexers(newIndexers Indexers[T]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name := range newIndexers {
+		if _, ok := s.indexers[name]; ok {
+			return newIndexConflictError(name)
+		}
+	}
+	for name, indexFunc := range newIndexers {
+		s.indexers[name] = indexFunc
+		idx := index{}
+		for key, obj := range s.items {
+			idx.add(indexFunc(obj), key)
+		}
+		s.indices[name] = idx
+	}
+	return nil
+}
+
+// updateIndices must be called with store lock held. Old or new object can be nil
+// for added or deleted objects.
+func (s *store[T]) updateIndices(oldObj, newObj *T, key string) {
+	for name, indexFunc := range s.indexers {
+		idx := s.indices[name]
+		if oldObj != nil {
+			idx.delete(indexFunc(oldObj), key)
+		}
+		if newObj != nil {
+			idx.add(indexFunc(newObj), key)
+		}
+	}
+}
+
+func (idx index) add(values []string, key string) {
+	for _, value := range values {
+		keys, ok := idx[value]
+		if !ok {
+			keys = map[string]struct{}{}
+			idx[value] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+func (idx index) delete(values []string, key string) {
+	for _, value := range values {
+		keys, ok := idx[value]
+		if !ok {
+			continue
+		}
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(idx, value)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"sync"
+)
+
+// ClientCertificateLoader provides client certificate for mTLS authentication. Certificate and key can be
+// set as PEM data or as files. Files are read on every TLS handshake and reloaded when their content changes,
+// so rotated certificates are used for new connections without restart.
+type ClientCertificateLoader struct {
+	certFile string
+	keyFile  string
+	certData []byte
+	keyData  []byte
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// NewClientCertificateLoader creates loader and loads the certificate. PEM data takes precedence over files.
+func NewClientCertificateLoader(certFile, keyFile string, certData, keyData []byte) (*ClientCertificateLoader, error) {
+	if len(certData) == 0 && certFile == "" {
+		return nil, fmt.Errorf("client certificate is not set")
+	}
+	if len(keyData) == 0 && keyFile == "" {
+		return nil, fmt.Errorf("client key is not set")
+	}
+	l := &ClientCertificateLoader{
+		certData: certData,
+		keyData:  keyData,
+	}
+	if len(certData) == 0 {
+		l.certFile = certFile
+	}
+	if len(keyData) == 0 {
+		l.keyFile = keyFile
+	}
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// GetClientCertificate can be used as tls.Config GetClientCertificate callback. If changed files can't be
+// loaded, e.g. when certificate is already written but key is not yet, previous certificate is returned.
+func (l *ClientCertificateLoader) GetClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.load(); err != nil && l.cert == nil {
+		return nil, err
+	}
+	return l.cert, nil
+}
+
+// load reads certificate and key and parses them if they differ from loaded ones. Must be called with lock held.
+func (l *ClientCertificateLoader) load() error {
+	certPEM, err := readDataOrFile(l.certData, l.certFile)
+	if err != nil {
+		return fmt.Errorf("reading client certificate: %w", err)
+	}
+	keyPEM, err := readDataOrFile(l.keyData, l.keyFile)
+	if err != nil {
+		return fmt.Errorf("reading client key: %w", err)
+	}
+	if l.cert != nil && bytes.Equal(certPEM, l.certPEM) && bytes.Equal(keyPEM, l.keyPEM) {
+		return nil
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("loading client certificate: %w", err)
+	}
+	l.cert = &cert
+	l.certPEM, l.keyPEM = certPEM, keyPEM
+	return nil
+}
+
+func readDataOrFile(data []byte, name string) ([]byte, error) {
+	if len(data) > 0 {
+		return data, nil
+	}
+	return ioutil.ReadFile(name)
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+func TestClientCertificateKubeconfig(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCert := func(cn string) (certPEM, keyPEM []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(time.Now().UnixNano()),
+			Subject:      pkix.Name{CommonName: cn},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(caCert)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoints := corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: r.TLS.PeerCertificates[0].Subject.CommonName}}
+		if err := json.NewEncoder(w).Encode(endpoints); err != nil {
+			t.Error(err)
+		}
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+
+	dir := t.TempDir()
+	certPEM, keyPEM := clientCert("agent1")
+	writeFile(t, filepath.Join(dir, "client.crt"), string(certPEM))
+	writeFile(t, filepath.Join(dir, "client.key"), string(keyPEM))
+	writeFile(t, filepath.Join(dir, "config"), `
+current-context: test
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+clusters:
+- name: test
+  cluster:
+    server: `+srv.URL+`
+    insecure-skip-tls-verify: true
+users:
+- name: test
+  user:
+    client-certificate: client.crt
+    client-key: client.key
+`)
+
+	kc, err := NewFromKubeconfig(filepath.Join(dir, "config"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := NewObjectAPI[corev1.Endpoints](kc)
+	expectCommonName := func(expected string) {
+		t.Helper()
+		endpoints, err := api.Get(context.Background(), "", "endpoint", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if endpoints.Name != expected {
+			t.Fatalf("expected client certificate %q, got %q", expected, endpoints.Name)
+		}
+	}
+	expectCommonName("agent1")
+
+	// Rotated files are used for new connections.
+	certPEM, keyPEM = clientCert("agent2")
+	writeFile(t, filepath.Join(dir, "client.crt"), string(certPEM))
+	writeFile(t, filepath.Join(dir, "client.key"), string(keyPEM))
+	kc.HttpClient.CloseIdleConnections()
+	expectCommonName("agent2")
+}
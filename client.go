@@ -1,26 +1,27 @@
 package client
 
 import (
+	"bytes"
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net"
 	"net/http"
-	"os"
+	"net/url"
 	"path"
-	"sync"
-	"time"
-
-	"github.com/fsnotify/fsnotify"
+	"reflect"
+	"strconv"
+	"strings"
 
 	corev1 "github.com/castai/k8s-client-go/types/core/v1"
 	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
 )
 
+const (
+	contentTypeJSON = "application/json"
+)
+
 const (
 	serviceAccountToken  = "/var/run/secrets/kubernetes.io/serviceaccount/token"
 	serviceAccountCACert = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
@@ -38,67 +39,11 @@ type Interface interface {
 
 // NewInCluster creates Client if it is inside Kubernetes.
 func NewInCluster() (*DefaultClient, error) {
-	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
-	if len(host) == 0 || len(port) == 0 {
-		return nil, fmt.Errorf("unable to load in-cluster configuration, KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT must be defined")
-	}
-	token, err := ioutil.ReadFile(serviceAccountToken)
-	if err != nil {
-		return nil, err
-	}
-	ca, err := ioutil.ReadFile(serviceAccountCACert)
-	if err != nil {
-		return nil, err
-	}
-	certPool := x509.NewCertPool()
-	certPool.AppendCertsFromPEM(ca)
-	transport := &http.Transport{TLSClientConfig: &tls.Config{
-		MinVersion: tls.VersionTLS12,
-		RootCAs:    certPool,
-	}}
-	httpClient := &http.Client{Transport: transport, Timeout: time.Nanosecond * 0}
-
-	client := &DefaultClient{
-		apiServerURL: "https://" + net.JoinHostPort(host, port),
-		token:        string(token),
-		HttpClient:   httpClient,
-	}
-
-	// Create a new file watcher to listen for new Service Account tokens
-	watcher, err := fsnotify.NewWatcher()
+	cfg, err := InClusterConfig()
 	if err != nil {
 		return nil, err
 	}
-
-	go func() {
-		for {
-			select {
-			case event, ok := <-watcher.Events:
-				if !ok {
-					return
-				}
-				if event.Op&fsnotify.Write == fsnotify.Write {
-					token, err := ioutil.ReadFile(serviceAccountToken)
-					if err == nil {
-						client.tokenMu.Lock()
-						client.token = string(token)
-						client.tokenMu.Unlock()
-					}
-				}
-			case _, ok := <-watcher.Errors:
-				if !ok {
-					return
-				}
-			}
-		}
-	}()
-
-	err = watcher.Add(serviceAccountToken)
-	if err != nil {
-		return nil, err
-	}
-
-	return client, nil
+	return NewForConfig(cfg)
 }
 
 type DefaultClient struct {
@@ -106,19 +51,42 @@ type DefaultClient struct {
 	//Logger     Logger
 	apiServerURL string
 
-	tokenMu sync.RWMutex
-	token   string
+	tokenSource TokenSource
 }
 
+// Do sends request with token from client TokenSource. Request is retried once with refreshed token
+// if API server responds with 401.
 func (kc *DefaultClient) Do(req *http.Request) (*http.Response, error) {
+	if kc.tokenSource != nil {
+		return roundTripWithToken(kc.HttpClient, kc.tokenSource, req)
+	}
 	return kc.HttpClient.Do(req)
 }
 
+// tokenClient is implemented by clients which set access token in Do, so it's not set on request creation.
+type tokenClient interface {
+	setsToken() bool
+}
+
+func (kc *DefaultClient) setsToken() bool {
+	return kc.tokenSource != nil
+}
+
 func (kc *DefaultClient) Token() string {
-	kc.tokenMu.RLock()
-	defer kc.tokenMu.RUnlock()
+	if kc.tokenSource == nil {
+		return ""
+	}
+	token, _ := kc.tokenSource.Token()
+	return token
+}
 
-	return kc.token
+// Close stops background token refresh and closes idle connections.
+func (kc *DefaultClient) Close() error {
+	kc.HttpClient.CloseIdleConnections()
+	if closer, ok := kc.tokenSource.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
 }
 
 func (kc *DefaultClient) APIServerURL() string {
@@ -131,6 +99,7 @@ type ObjectAPIOption func(opts *objectAPIOptions)
 type objectAPIOptions struct {
 	log                Logger
 	responseDecodeFunc ResponseDecoderFunc
+	skipBookmarks      bool
 }
 
 func WithLogger(log Logger) ObjectAPIOption {
@@ -145,6 +114,14 @@ func WithResponseDecoder(decoderFunc ResponseDecoderFunc) ObjectAPIOption {
 	}
 }
 
+// WithSkipBookmarks filters out BOOKMARK events from watch result channel. Resource version
+// from bookmarks is still tracked and available via WatchInterface.ResourceVersion.
+func WithSkipBookmarks() ObjectAPIOption {
+	return func(opts *objectAPIOptions) {
+		opts.skipBookmarks = true
+	}
+}
+
 func NewObjectAPI[T corev1.Object](kc Interface, opt ...ObjectAPIOption) ObjectAPI[T] {
 	opts := objectAPIOptions{
 		log: &DefaultLogger{},
@@ -167,7 +144,7 @@ type objectAPI[T corev1.Object] struct {
 	opts objectAPIOptions
 }
 
-func buildRequestURL(apiServerURL string, gvr metav1.GroupVersionResource, namespace, name string) string {
+func buildRequestURL(apiServerURL string, gvr metav1.GroupVersionResource, namespace, name string, query url.Values) string {
 	var gvrPath string
 	if gvr.Group == "" {
 		gvrPath = path.Join("api", gvr.Version)
@@ -178,35 +155,303 @@ func buildRequestURL(apiServerURL string, gvr metav1.GroupVersionResource, names
 	if namespace != "" {
 		nsPath = path.Join("namespaces", namespace)
 	}
-	return apiServerURL + "/" + path.Join(gvrPath, nsPath, gvr.Resource, name)
+	reqURL := apiServerURL + "/" + path.Join(gvrPath, nsPath, gvr.Resource, name)
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	return reqURL
+}
+
+func getOptionsQuery(opts metav1.GetOptions) url.Values {
+	query := url.Values{}
+	if opts.ResourceVersion != "" {
+		query.Set("resourceVersion", opts.ResourceVersion)
+	}
+	return query
+}
+
+func listOptionsQuery(opts metav1.ListOptions) url.Values {
+	query := url.Values{}
+	if opts.LabelSelector != "" {
+		query.Set("labelSelector", opts.LabelSelector)
+	}
+	if opts.FieldSelector != "" {
+		query.Set("fieldSelector", opts.FieldSelector)
+	}
+	if opts.AllowWatchBookmarks {
+		query.Set("allowWatchBookmarks", "true")
+	}
+	if opts.ResourceVersion != "" {
+		query.Set("resourceVersion", opts.ResourceVersion)
+	}
+	if opts.ResourceVersionMatch != "" {
+		query.Set("resourceVersionMatch", string(opts.ResourceVersionMatch))
+	}
+	if opts.TimeoutSeconds != nil {
+		query.Set("timeoutSeconds", strconv.FormatInt(*opts.TimeoutSeconds, 10))
+	}
+	if opts.SendInitialEvents != nil {
+		query.Set("sendInitialEvents", strconv.FormatBool(*opts.SendInitialEvents))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.FormatInt(opts.Limit, 10))
+	}
+	if opts.Continue != "" {
+		query.Set("continue", opts.Continue)
+	}
+	return query
+}
+
+func writeOptionsQuery(dryRun []string, fieldManager string) url.Values {
+	query := url.Values{}
+	for _, v := range dryRun {
+		query.Add("dryRun", v)
+	}
+	if fieldManager != "" {
+		query.Set("fieldManager", fieldManager)
+	}
+	return query
 }
 
 func (o *objectAPI[T]) Get(ctx context.Context, namespace, name string, opts metav1.GetOptions) (*T, error) {
 	var t T
-	reqURL := buildRequestURL(o.kc.APIServerURL(), t.GVR(), namespace, name)
-	req, err := o.getRequest(ctx, reqURL)
+	reqURL := buildRequestURL(o.kc.APIServerURL(), t.GVR(), namespace, name, getOptionsQuery(opts))
+	resp, err := o.doRequest(ctx, http.MethodGet, reqURL, nil, "")
 	if err != nil {
 		return nil, err
 	}
-	resp, err := o.kc.Do(req)
+	defer resp.Body.Close()
+	return o.decodeObject(resp)
+}
+
+func (o *objectAPI[T]) List(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.List[T], error) {
+	var t T
+	reqURL := buildRequestURL(o.kc.APIServerURL(), t.GVR(), namespace, "", listOptionsQuery(opts))
+	resp, err := o.doRequest(ctx, http.MethodGet, reqURL, nil, "")
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		errmsg, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("invalid response code %d for request url %q: %s", resp.StatusCode, reqURL, errmsg)
-	}
-	if err := o.opts.responseDecodeFunc(resp.Body).Decode(&t); err != nil {
+	var list corev1.List[T]
+	if err := o.opts.responseDecodeFunc(resp.Body).Decode(&list); err != nil {
 		return nil, err
 	}
-	return &t, err
+	return &list, nil
 }
 
 func (o *objectAPI[T]) Watch(ctx context.Context, namespace, name string, opts metav1.ListOptions) (WatchInterface[T], error) {
 	var t T
-	reqURL := buildRequestURL(o.kc.APIServerURL(), t.GVR(), namespace, name)
-	req, err := o.getRequest(ctx, reqURL)
+	query := listOptionsQuery(opts)
+	query.Set("watch", "1")
+	if name != "" {
+		// API server watches collections only, single object is selected by its name field.
+		fieldSelector := "metadata.name=" + name
+		if opts.FieldSelector != "" {
+			fieldSelector += "," + opts.FieldSelector
+		}
+		query.Set("fieldSelector", fieldSelector)
+	}
+	reqURL := buildRequestURL(o.kc.APIServerURL(), t.GVR(), namespace, "", query)
+	resp, err := o.doRequest(ctx, http.MethodGet, reqURL, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return newStreamWatcher[T](resp.Body, o.opts.log, o.opts.responseDecodeFunc, o.opts.skipBookmarks), nil
+}
+
+func (o *objectAPI[T]) Create(ctx context.Context, namespace string, obj *T, opts metav1.CreateOptions) (*T, error) {
+	var t T
+	body, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	reqURL := buildRequestURL(o.kc.APIServerURL(), t.GVR(), namespace, "", writeOptionsQuery(opts.DryRun, opts.FieldManager))
+	resp, err := o.doRequest(ctx, http.MethodPost, reqURL, bytes.NewReader(body), contentTypeJSON)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return o.decodeObject(resp)
+}
+
+func (o *objectAPI[T]) Update(ctx context.Context, namespace string, obj *T, opts metav1.UpdateOptions) (*T, error) {
+	name := (*obj).GetObjectMeta().Name
+	if name == "" {
+		return nil, fmt.Errorf("object name is required for update")
+	}
+	body, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	reqURL := buildRequestURL(o.kc.APIServerURL(), (*obj).GVR(), namespace, name, writeOptionsQuery(opts.DryRun, opts.FieldManager))
+	resp, err := o.doRequest(ctx, http.MethodPut, reqURL, bytes.NewReader(body), contentTypeJSON)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return o.decodeObject(resp)
+}
+
+func (o *objectAPI[T]) Delete(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error {
+	var t T
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+	reqURL := buildRequestURL(o.kc.APIServerURL(), t.GVR(), namespace, name, nil)
+	resp, err := o.doRequest(ctx, http.MethodDelete, reqURL, bytes.NewReader(body), contentTypeJSON)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+func (o *objectAPI[T]) Patch(ctx context.Context, namespace, name string, pt metav1.PatchType, data []byte, opts metav1.PatchOptions) (*T, error) {
+	var t T
+	if name == "" {
+		return nil, fmt.Errorf("object name is required for patch")
+	}
+	switch pt {
+	case metav1.JSONPatchType, metav1.MergePatchType, metav1.StrategicMergePatchType, metav1.ApplyPatchType:
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", pt)
+	}
+	if opts.Force != nil && pt != metav1.ApplyPatchType {
+		return nil, fmt.Errorf("force is allowed only for apply patch")
+	}
+	query := writeOptionsQuery(opts.DryRun, opts.FieldManager)
+	if opts.Force != nil {
+		query.Set("force", strconv.FormatBool(*opts.Force))
+	}
+	reqURL := buildRequestURL(o.kc.APIServerURL(), t.GVR(), namespace, name, query)
+	resp, err := o.doRequest(ctx, http.MethodPatch, reqURL, bytes.NewReader(data), string(pt))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return o.decodeObject(resp)
+}
+
+func (o *objectAPI[T]) Apply(ctx context.Context, obj *T, opts metav1.ApplyOptions) (*T, error) {
+	if opts.FieldManager == "" {
+		return nil, fmt.Errorf("field manager is required for apply")
+	}
+	meta, typeMeta := (*obj).GetObjectMeta(), (*obj).GetTypeMeta()
+	if meta.Name == "" {
+		return nil, fmt.Errorf("object name is required for apply")
+	}
+	if typeMeta.APIVersion == "" || typeMeta.Kind == "" {
+		return nil, fmt.Errorf("object apiVersion and kind are required for apply")
+	}
+	data, err := encodeApplyPatch(obj)
+	if err != nil {
+		return nil, err
+	}
+	return o.Patch(ctx, meta.Namespace, meta.Name, metav1.ApplyPatchType, data, metav1.PatchOptions{
+		DryRun:       opts.DryRun,
+		Force:        &opts.Force,
+		FieldManager: opts.FieldManager,
+	})
+}
+
+// encodeApplyPatch encodes object without zero value struct fields, so field manager owns only fields set
+// in obj. Map entries and list items are kept as is. JSON is a subset of YAML, so encoded object can be sent
+// as apply patch.
+func encodeApplyPatch(obj any) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	pruneZeroFields(reflect.ValueOf(obj), fields)
+	return json.Marshal(fields)
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// pruneZeroFields removes fields of zero value struct fields from encoded value of v.
+func pruneZeroFields(v reflect.Value, encoded any) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	// Types with custom encoding are kept as is.
+	if v.Type().Implements(jsonMarshalerType) || reflect.PointerTo(v.Type()).Implements(jsonMarshalerType) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if fields, ok := encoded.(map[string]any); ok {
+			pruneStructFields(v, fields)
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := encoded.([]any)
+		if !ok || len(items) != v.Len() {
+			return
+		}
+		for i := range items {
+			pruneZeroFields(v.Index(i), items[i])
+		}
+	case reflect.Map:
+		entries, ok := encoded.(map[string]any)
+		if !ok || v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			if entry, ok := entries[iter.Key().String()]; ok {
+				pruneZeroFields(iter.Value(), entry)
+			}
+		}
+	}
+}
+
+func pruneStructFields(v reflect.Value, fields map[string]any) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		fv := v.Field(i)
+		// Fields of embedded structs without name are encoded inline.
+		if f.Anonymous && name == "" {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				pruneStructFields(fv, fields)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if fv.IsZero() {
+			delete(fields, name)
+		} else if encoded, ok := fields[name]; ok {
+			pruneZeroFields(fv, encoded)
+		}
+	}
+}
+
+// doRequest sends request to API server and returns response only if it has successful status code.
+// Otherwise, *StatusError is returned.
+// Caller is responsible for closing response body.
+func (o *objectAPI[T]) doRequest(ctx context.Context, method, reqURL string, body io.Reader, contentType string) (*http.Response, error) {
+	req, err := o.newRequest(ctx, method, reqURL, body, contentType)
 	if err != nil {
 		return nil, err
 	}
@@ -214,19 +459,33 @@ func (o *objectAPI[T]) Watch(ctx context.Context, namespace, name string, opts m
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
 		defer resp.Body.Close()
 		errmsg, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("invalid response code %d for request url %q: %s", resp.StatusCode, reqURL, errmsg)
+		return nil, newStatusError(o.opts.responseDecodeFunc, resp.StatusCode, reqURL, errmsg)
 	}
-	return newStreamWatcher[T](resp.Body, o.opts.log, o.opts.responseDecodeFunc(resp.Body)), nil
+	return resp, nil
 }
 
-func (o *objectAPI[T]) getRequest(ctx context.Context, url string) (*http.Request, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+func (o *objectAPI[T]) decodeObject(resp *http.Response) (*T, error) {
+	var t T
+	if err := o.opts.responseDecodeFunc(resp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (o *objectAPI[T]) newRequest(ctx context.Context, method, url string, body io.Reader, contentType string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if tc, ok := o.kc.(tokenClient); ok && tc.setsToken() {
+		return req, nil
+	}
 	if token := o.kc.Token(); len(token) > 0 {
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
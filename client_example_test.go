@@ -26,6 +26,13 @@ func ExampleObjectAPI() {
 	}
 	fmt.Printf("%+v\n", endpoints)
 
+	endpointsList, err := client.ListAll[corev1.Endpoints](ctx, endpointsAPI, "kube-system", metav1.ListOptions{Limit: 500})
+	if err != nil {
+		// Handle err
+		return
+	}
+	fmt.Printf("%+v\n", endpointsList.Items)
+
 	events, err := endpointsAPI.Watch(ctx, "kube-system", "kubelet", metav1.ListOptions{})
 	if err != nil {
 		// Handle err
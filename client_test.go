@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -53,6 +54,405 @@ func TestClientAPIGet(t *testing.T) {
 	}
 }
 
+func TestClientAPIGetStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		status := metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusFailure,
+			Message:  `endpoints "endpoint1" not found`,
+			Reason:   metav1.StatusReasonNotFound,
+			Code:     http.StatusNotFound,
+		}
+		if err := json.NewEncoder(w).Encode(status); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer srv.Close()
+	client := &mockClient{
+		apiServerURL: srv.URL,
+		hc:           &http.Client{Timeout: 5 * time.Second},
+	}
+
+	api := NewObjectAPI[corev1.Endpoints](client)
+
+	_, err := api.Get(context.Background(), "test", "endpoint1", metav1.GetOptions{})
+	if !IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if IsConflict(err) {
+		t.Fatalf("expected not to be conflict error")
+	}
+	if err.Error() != `endpoints "endpoint1" not found` {
+		t.Fatalf("unexpected error message %q", err.Error())
+	}
+	if wrapped := fmt.Errorf("get: %w", err); !IsNotFound(wrapped) {
+		t.Fatalf("expected wrapped not found error, got %v", wrapped)
+	}
+}
+
+func TestClientAPIListAll(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := "/api/v1/namespaces/test/endpoints"
+		if r.URL.Path != expectedPath {
+			t.Fatalf("expected request path %q, got %q", expectedPath, r.URL.Path)
+		}
+		if limit := r.URL.Query().Get("limit"); limit != "1" {
+			t.Fatalf("expected limit 1, got %q", limit)
+		}
+
+		list := corev1.List[corev1.Endpoints]{
+			ListMeta: metav1.ListMeta{ResourceVersion: "10"},
+		}
+		switch r.URL.Query().Get("continue") {
+		case "":
+			list.Continue = "page2"
+			list.Items = []corev1.Endpoints{{ObjectMeta: metav1.ObjectMeta{Name: "endpoint1"}}}
+		case "page2":
+			list.Items = []corev1.Endpoints{{ObjectMeta: metav1.ObjectMeta{Name: "endpoint2"}}}
+		default:
+			t.Fatalf("unexpected continue token %q", r.URL.Query().Get("continue"))
+		}
+		if err := json.NewEncoder(w).Encode(list); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer srv.Close()
+	client := &mockClient{
+		apiServerURL: srv.URL,
+		hc:           &http.Client{Timeout: 5 * time.Second},
+	}
+
+	api := NewObjectAPI[corev1.Endpoints](client)
+
+	res, err := ListAll[corev1.Endpoints](context.Background(), api, "test", metav1.ListOptions{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ResourceVersion != "10" {
+		t.Fatalf("expected resource version %q, got %q", "10", res.ResourceVersion)
+	}
+	if len(res.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(res.Items))
+	}
+	if res.Items[0].Name != "endpoint1" || res.Items[1].Name != "endpoint2" {
+		t.Fatalf("unexpected items %+v", res.Items)
+	}
+}
+
+func TestClientAPIWatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := "/api/v1/namespaces/test/endpoints"
+		if r.URL.Path != expectedPath {
+			t.Fatalf("expected request path %q, got %q", expectedPath, r.URL.Path)
+		}
+		if watch := r.URL.Query().Get("watch"); watch != "1" {
+			t.Fatalf("expected watch query 1, got %q", watch)
+		}
+		expectedFieldSelector := "metadata.name=endpoint1"
+		if fieldSelector := r.URL.Query().Get("fieldSelector"); fieldSelector != expectedFieldSelector {
+			t.Fatalf("expected field selector %q, got %q", expectedFieldSelector, fieldSelector)
+		}
+		expectedLabelSelector := "app=test"
+		if labelSelector := r.URL.Query().Get("labelSelector"); labelSelector != expectedLabelSelector {
+			t.Fatalf("expected label selector %q, got %q", expectedLabelSelector, labelSelector)
+		}
+		if timeout := r.URL.Query().Get("timeoutSeconds"); timeout != "30" {
+			t.Fatalf("expected timeout seconds 30, got %q", timeout)
+		}
+
+		enc := json.NewEncoder(w)
+		for _, eventType := range []corev1.EventType{corev1.EventTypeAdded, corev1.EventTypeDeleted} {
+			event := corev1.Event[corev1.Endpoints]{
+				Type: eventType,
+				Object: &corev1.Endpoints{
+					ObjectMeta: metav1.ObjectMeta{Name: "endpoint1", Namespace: "test"},
+				},
+			}
+			if err := enc.Encode(event); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}))
+	defer srv.Close()
+	client := &mockClient{
+		apiServerURL: srv.URL,
+		hc:           &http.Client{Timeout: 5 * time.Second},
+	}
+
+	api := NewObjectAPI[corev1.Endpoints](client)
+
+	timeout := int64(30)
+	w, err := api.Watch(context.Background(), "test", "endpoint1", metav1.ListOptions{
+		LabelSelector:  "app=test",
+		TimeoutSeconds: &timeout,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	var events []corev1.EventType
+	for e := range w.ResultChan() {
+		if e.Object.Name != "endpoint1" {
+			t.Fatalf("expected name %q, got %q", "endpoint1", e.Object.Name)
+		}
+		events = append(events, e.Type)
+	}
+	if len(events) != 2 || events[0] != corev1.EventTypeAdded || events[1] != corev1.EventTypeDeleted {
+		t.Fatalf("unexpected events %v", events)
+	}
+}
+
+func TestClientAPIWrite(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			if r.URL.Path != "/api/v1/namespaces/test/endpoints" {
+				t.Fatalf("unexpected create path %q", r.URL.Path)
+			}
+			if fieldManager := r.URL.Query().Get("fieldManager"); fieldManager != "test-manager" {
+				t.Fatalf("expected field manager %q, got %q", "test-manager", fieldManager)
+			}
+		case http.MethodPut:
+			if r.URL.Path != "/api/v1/namespaces/test/endpoints/endpoint1" {
+				t.Fatalf("unexpected update path %q", r.URL.Path)
+			}
+		case http.MethodDelete:
+			if r.URL.Path != "/api/v1/namespaces/test/endpoints/endpoint1" {
+				t.Fatalf("unexpected delete path %q", r.URL.Path)
+			}
+			var opts metav1.DeleteOptions
+			if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+				t.Fatal(err)
+			}
+			if opts.GracePeriodSeconds == nil || *opts.GracePeriodSeconds != 0 {
+				t.Fatalf("expected zero grace period, got %v", opts.GracePeriodSeconds)
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		default:
+			t.Fatalf("unexpected method %q", r.Method)
+		}
+		if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+			t.Fatalf("expected json content type, got %q", contentType)
+		}
+		var endpoints corev1.Endpoints
+		if err := json.NewDecoder(r.Body).Decode(&endpoints); err != nil {
+			t.Fatal(err)
+		}
+		endpoints.ResourceVersion = "1"
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(endpoints); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer srv.Close()
+	client := &mockClient{
+		apiServerURL: srv.URL,
+		hc:           &http.Client{Timeout: 5 * time.Second},
+	}
+
+	api := NewObjectAPI[corev1.Endpoints](client)
+	ctx := context.Background()
+
+	obj := &corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "endpoint1"}}
+	created, err := api.Create(ctx, "test", obj, metav1.CreateOptions{FieldManager: "test-manager"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ResourceVersion != "1" {
+		t.Fatalf("expected resource version %q, got %q", "1", created.ResourceVersion)
+	}
+	if _, err := api.Update(ctx, "test", created, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	gracePeriod := int64(0)
+	if err := api.Delete(ctx, "test", "endpoint1", metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientAPIPatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Fatalf("expected method %q, got %q", http.MethodPatch, r.Method)
+		}
+		if r.URL.Path != "/api/v1/namespaces/test/endpoints/endpoint1" {
+			t.Fatalf("unexpected patch path %q", r.URL.Path)
+		}
+		if contentType := r.Header.Get("Content-Type"); contentType != string(metav1.MergePatchType) {
+			t.Fatalf("expected content type %q, got %q", metav1.MergePatchType, contentType)
+		}
+		var patch struct {
+			Metadata metav1.ObjectMeta `json:"metadata"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			t.Fatal(err)
+		}
+		endpoints := corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "endpoint1",
+				Namespace:   "test",
+				Annotations: patch.Metadata.Annotations,
+			},
+		}
+		if err := json.NewEncoder(w).Encode(endpoints); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer srv.Close()
+	client := &mockClient{
+		apiServerURL: srv.URL,
+		hc:           &http.Client{Timeout: 5 * time.Second},
+	}
+
+	api := NewObjectAPI[corev1.Endpoints](client)
+
+	patch := []byte(`{"metadata":{"annotations":{"key":"value"}}}`)
+	res, err := api.Patch(context.Background(), "test", "endpoint1", metav1.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Annotations["key"] != "value" {
+		t.Fatalf("expected patched annotation, got %v", res.Annotations)
+	}
+
+	if _, err := api.Patch(context.Background(), "test", "", metav1.MergePatchType, patch, metav1.PatchOptions{}); err == nil {
+		t.Fatal("expected error for empty name")
+	}
+	force := true
+	if _, err := api.Patch(context.Background(), "test", "endpoint1", metav1.MergePatchType, patch, metav1.PatchOptions{Force: &force}); err == nil {
+		t.Fatal("expected error for force with merge patch")
+	}
+}
+
+func TestClientAPIApply(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/test/endpoints/endpoint1" {
+			t.Fatalf("unexpected apply path %q", r.URL.Path)
+		}
+		if contentType := r.Header.Get("Content-Type"); contentType != string(metav1.ApplyPatchType) {
+			t.Fatalf("expected content type %q, got %q", metav1.ApplyPatchType, contentType)
+		}
+		query := r.URL.Query()
+		if query.Get("fieldManager") != "test-manager" || query.Get("force") != "true" {
+			t.Fatalf("unexpected apply query %q", r.URL.RawQuery)
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Only fields set in applied object are sent.
+		expected := `{"apiVersion":"v1","kind":"Endpoints","metadata":{"labels":{"a":"b","node-role.kubernetes.io/control-plane":""},"name":"endpoint1","namespace":"test"},"subsets":[{"addresses":[{"ip":"10.0.0.1"}]}]}`
+		if string(body) != expected {
+			t.Fatalf("expected apply body %s, got %s", expected, body)
+		}
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer srv.Close()
+	client := &mockClient{
+		apiServerURL: srv.URL,
+		hc:           &http.Client{Timeout: 5 * time.Second},
+	}
+
+	api := NewObjectAPI[corev1.Endpoints](client)
+
+	obj := &corev1.Endpoints{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Endpoints"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "endpoint1",
+			Namespace: "test",
+			// Labels with empty values are kept.
+			Labels: map[string]string{"a": "b", "node-role.kubernetes.io/control-plane": ""},
+		},
+		Subsets: []corev1.Subset{{Addresses: []corev1.Address{{IP: "10.0.0.1"}}}},
+	}
+	res, err := api.Apply(context.Background(), obj, metav1.ApplyOptions{FieldManager: "test-manager", Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Name != "endpoint1" {
+		t.Fatalf("expected name %q, got %q", "endpoint1", res.Name)
+	}
+
+	if _, err := api.Apply(context.Background(), obj, metav1.ApplyOptions{}); err == nil {
+		t.Fatal("expected error without field manager")
+	}
+}
+
+func TestClientAPIWatchErrorEvent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"type":"ERROR","object":{"kind":"Status","apiVersion":"v1","status":"Failure","message":"too old resource version: 1 (10)","reason":"Expired","code":410}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer srv.Close()
+	client := &mockClient{
+		apiServerURL: srv.URL,
+		hc:           &http.Client{Timeout: 5 * time.Second},
+	}
+
+	api := NewObjectAPI[corev1.Endpoints](client)
+
+	w, err := api.Watch(context.Background(), "test", "", metav1.ListOptions{ResourceVersion: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	e, ok := <-w.ResultChan()
+	if !ok {
+		t.Fatal("expected error event")
+	}
+	if e.Type != corev1.EventTypeError || e.Object != nil || e.Status == nil {
+		t.Fatalf("unexpected event %+v", e)
+	}
+	if !IsGone(&StatusError{ErrStatus: *e.Status}) {
+		t.Fatalf("expected gone status, got %+v", e.Status)
+	}
+}
+
+func TestClientAPIWatchBookmarks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("allowWatchBookmarks") != "true" {
+			t.Fatalf("expected bookmarks to be allowed, got query %q", r.URL.RawQuery)
+		}
+		_, err := w.Write([]byte(`{"type":"ADDED","object":{"metadata":{"name":"endpoint1","resourceVersion":"5"}}}
+{"type":"BOOKMARK","object":{"metadata":{"resourceVersion":"7"}}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer srv.Close()
+	client := &mockClient{
+		apiServerURL: srv.URL,
+		hc:           &http.Client{Timeout: 5 * time.Second},
+	}
+
+	api := NewObjectAPI[corev1.Endpoints](client, WithSkipBookmarks())
+
+	w, err := api.Watch(context.Background(), "test", "", metav1.ListOptions{AllowWatchBookmarks: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	var events []corev1.EventType
+	for e := range w.ResultChan() {
+		events = append(events, e.Type)
+	}
+	if len(events) != 1 || events[0] != corev1.EventTypeAdded {
+		t.Fatalf("expected only added event, got %v", events)
+	}
+	if rv := w.ResourceVersion(); rv != "7" {
+		t.Fatalf("expected resource version %q, got %q", "7", rv)
+	}
+}
+
 type mockClient struct {
 	apiServerURL string
 	hc           *http.Client
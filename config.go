@@ -0,0 +1,143 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config holds the common attributes that can be passed to NewForConfig.
+type Config struct {
+	// Host is API server URL or host:port. HTTPS is used if scheme is not set.
+	Host string
+
+	// CAFile is path to PEM encoded certificate authority bundle. CAData takes precedence over CAFile.
+	CAFile string
+	CAData []byte
+	// TLSServerName is used to verify API server certificate. If empty, host name is used.
+	TLSServerName string
+	// Insecure skips API server certificate verification. Use only for testing.
+	Insecure bool
+
+	// BearerToken is static access token.
+	BearerToken string
+	// BearerTokenFile is path to a file containing access token. The file is watched for changes, including
+	// symlink swaps of projected service account tokens, and re-read periodically. Its content takes
+	// precedence over BearerToken.
+	BearerTokenFile string
+	// TokenSource provides access token and takes precedence over BearerToken and BearerTokenFile.
+	TokenSource TokenSource
+
+	// CertFile and KeyFile are paths to client certificate and key for mTLS authentication. Files are
+	// reloaded when they change on disk. CertData and KeyData take precedence over files.
+	CertFile string
+	KeyFile  string
+	CertData []byte
+	KeyData  []byte
+
+	// Proxy returns proxy URL for a request. If nil, http.ProxyFromEnvironment is used. To disable proxy,
+	// set function which returns nil URL.
+	Proxy func(*http.Request) (*url.URL, error)
+
+	// Timeout is the maximum length of time to wait before giving up on a server request. Zero means
+	// no timeout. Note that timeout also limits duration of watch requests.
+	Timeout time.Duration
+}
+
+// InClusterConfig returns Config which uses service account Kubernetes gives to pods.
+func InClusterConfig() (*Config, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if len(host) == 0 || len(port) == 0 {
+		return nil, fmt.Errorf("unable to load in-cluster configuration, KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT must be defined")
+	}
+	return &Config{
+		Host:            "https://" + net.JoinHostPort(host, port),
+		CAFile:          serviceAccountCACert,
+		BearerTokenFile: serviceAccountToken,
+	}, nil
+}
+
+// NewForConfig creates Client for the given config.
+func NewForConfig(cfg *Config) (*DefaultClient, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("host must be set in config")
+	}
+	apiServerURL := cfg.Host
+	if !strings.Contains(apiServerURL, "://") {
+		apiServerURL = "https://" + apiServerURL
+	}
+	if _, err := url.Parse(apiServerURL); err != nil {
+		return nil, fmt.Errorf("parsing host: %w", err)
+	}
+
+	tlsConfig, err := configTLS(cfg)
+	if err != nil {
+		return nil, err
+	}
+	proxy := cfg.Proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	transport := &http.Transport{
+		Proxy:           proxy,
+		TLSClientConfig: tlsConfig,
+	}
+
+	tokenSource := cfg.TokenSource
+	switch {
+	case tokenSource != nil:
+	case cfg.BearerTokenFile != "":
+		tokenSource, err = NewFileTokenSource(cfg.BearerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+	case cfg.BearerToken != "":
+		tokenSource = StaticTokenSource(cfg.BearerToken)
+	}
+
+	return &DefaultClient{
+		apiServerURL: strings.TrimSuffix(apiServerURL, "/"),
+		HttpClient:   &http.Client{Transport: transport, Timeout: cfg.Timeout},
+		tokenSource:  tokenSource,
+	}, nil
+}
+
+func configTLS(cfg *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.Insecure,
+	}
+
+	ca := cfg.CAData
+	if len(ca) == 0 && cfg.CAFile != "" {
+		data, err := ioutil.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %w", err)
+		}
+		ca = data
+	}
+	if len(ca) > 0 {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no valid certificates found in certificate authority")
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	if len(cfg.CertData) > 0 || cfg.CertFile != "" || len(cfg.KeyData) > 0 || cfg.KeyFile != "" {
+		certLoader, err := NewClientCertificateLoader(cfg.CertFile, cfg.KeyFile, cfg.CertData, cfg.KeyData)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.GetClientCertificate = certLoader.GetClientCertificate
+	}
+	return tlsConfig, nil
+}
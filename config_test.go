@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+func TestNewForConfig(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer file-token" {
+			t.Errorf("expected bearer token from token file, got %q", auth)
+		}
+		endpoints := corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "endpoint1", Namespace: "test"}}
+		if err := json.NewEncoder(w).Encode(endpoints); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "token"), "file-token\n")
+
+	kc, err := NewForConfig(&Config{
+		Host:            strings.TrimPrefix(srv.URL, "https://"),
+		CAData:          pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}),
+		BearerToken:     "static-token",
+		BearerTokenFile: filepath.Join(dir, "token"),
+		Timeout:         5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kc.APIServerURL() != srv.URL {
+		t.Fatalf("expected api server url %q, got %q", srv.URL, kc.APIServerURL())
+	}
+	if kc.HttpClient.Timeout != 5*time.Second {
+		t.Fatalf("expected client timeout %v, got %v", 5*time.Second, kc.HttpClient.Timeout)
+	}
+	if transport, ok := kc.HttpClient.Transport.(*http.Transport); !ok || transport.Proxy == nil {
+		t.Fatal("expected proxy from environment to be used by default")
+	}
+
+	api := NewObjectAPI[corev1.Endpoints](kc)
+	if _, err := api.Get(context.Background(), "test", "endpoint1", metav1.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewForConfig(&Config{}); err == nil {
+		t.Fatal("expected error for empty host")
+	}
+}
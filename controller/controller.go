@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	client "github.com/castai/k8s-client-go"
+	"github.com/castai/k8s-client-go/cache"
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	"github.com/castai/k8s-client-go/workqueue"
+)
+
+// Request identifies object which should be reconciled.
+type Request struct {
+	Namespace string
+	Name      string
+}
+
+// Result is the result of reconciliation.
+type Result struct {
+	// Requeue tells controller to requeue the request with rate limiting backoff.
+	Requeue bool
+	// RequeueAfter tells controller to requeue the request after given duration. It takes precedence over Requeue.
+	RequeueAfter time.Duration
+}
+
+// Reconciler brings object to the desired state. Reconcile is called with the object namespace and name
+// on every change and object may be already deleted, in which case it should be handled as well.
+type Reconciler interface {
+	Reconcile(ctx context.Context, namespace, name string) (Result, error)
+}
+
+// ReconcilerFunc is an adapter to use ordinary functions as Reconciler.
+type ReconcilerFunc func(ctx context.Context, namespace, name string) (Result, error)
+
+// Reconcile calls f(ctx, namespace, name).
+func (f ReconcilerFunc) Reconcile(ctx context.Context, namespace, name string) (Result, error) {
+	return f(ctx, namespace, name)
+}
+
+type Option func(opts *options)
+type options struct {
+	workers     int
+	rateLimiter workqueue.RateLimiter[Request]
+	log         client.Logger
+}
+
+// WithWorkers sets number of concurrent reconcile workers. Default is 1.
+func WithWorkers(workers int) Option {
+	return func(opts *options) {
+		opts.workers = workers
+	}
+}
+
+// WithRateLimiter sets rate limiter used to requeue failed requests.
+// Default is workqueue.DefaultControllerRateLimiter.
+func WithRateLimiter(rateLimiter workqueue.RateLimiter[Request]) Option {
+	return func(opts *options) {
+		opts.rateLimiter = rateLimiter
+	}
+}
+
+// WithLogger sets logger used to report reconcile errors.
+func WithLogger(log client.Logger) Option {
+	return func(opts *options) {
+		opts.log = log
+	}
+}
+
+// Controller calls reconciler for every object of type T changed in informer cache. Requests for the same
+// object are deduplicated and never processed concurrently.
+type Controller[T corev1.Object] struct {
+	informer   cache.SharedInformer[T]
+	reconciler Reconciler
+	queue      workqueue.RateLimitingInterface[Request]
+	opts       options
+}
+
+// New creates controller and registers event handlers in informer. Informer can be shared with other
+// controllers, e.g. obtained from cache.SharedInformerFactory.
+func New[T corev1.Object](informer cache.SharedInformer[T], reconciler Reconciler, opt ...Option) *Controller[T] {
+	opts := options{
+		workers:     1,
+		rateLimiter: workqueue.DefaultControllerRateLimiter[Request](),
+		log:         &client.DefaultLogger{},
+	}
+	for _, o := range opt {
+		o(&opts)
+	}
+	c := &Controller[T]{
+		informer:   informer,
+		reconciler: reconciler,
+		queue:      workqueue.NewRateLimitingQueue[Request](opts.rateLimiter),
+		opts:       opts,
+	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs[T]{
+		AddFunc: c.enqueue,
+		UpdateFunc: func(_, newObj *T) {
+			c.enqueue(newObj)
+		},
+		DeleteFunc: func(obj *T, _ bool) {
+			c.enqueue(obj)
+		},
+	})
+	return c
+}
+
+func (c *Controller[T]) enqueue(obj *T) {
+	meta := (*obj).GetObjectMeta()
+	c.queue.Add(Request{Namespace: meta.Namespace, Name: meta.Name})
+}
+
+// Enqueue adds request for reconciliation, e.g. when object depends on other resources.
+func (c *Controller[T]) Enqueue(req Request) {
+	c.queue.Add(req)
+}
+
+// Run starts informer if it's not running yet, waits for cache sync and runs workers until context is done.
+// In-flight reconciles are finished before Run returns.
+func (c *Controller[T]) Run(ctx context.Context) error {
+	go c.informer.Run(ctx)
+
+	if !cache.WaitForCacheSync(ctx, c.informer.HasSynced) {
+		c.queue.ShutDown()
+		return fmt.Errorf("waiting for cache sync: %w", ctx.Err())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.opts.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c.processNextWorkItem(ctx) {
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	c.queue.ShutDown()
+	wg.Wait()
+	return nil
+}
+
+func (c *Controller[T]) processNextWorkItem(ctx context.Context) bool {
+	req, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(req)
+
+	if ctx.Err() != nil {
+		// Drain remaining items after shutdown without reconciling them.
+		return true
+	}
+
+	res, err := c.reconciler.Reconcile(ctx, req.Namespace, req.Name)
+	switch {
+	case err != nil:
+		c.opts.log.Infof("k8s-client-go: reconcile %s/%s failed: %v", req.Namespace, req.Name, err)
+		c.queue.AddRateLimited(req)
+	case res.RequeueAfter > 0:
+		c.queue.Forget(req)
+		c.queue.AddAfter(req, res.RequeueAfter)
+	case res.Requeue:
+		c.queue.AddRateLimited(req)
+	default:
+		c.queue.Forget(req)
+	}
+	return true
+}
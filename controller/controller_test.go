@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	client "github.com/castai/k8s-client-go"
+	"github.com/castai/k8s-client-go/cache"
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+	"github.com/castai/k8s-client-go/workqueue"
+)
+
+func TestController(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("watch") == "1" {
+			<-r.Context().Done()
+			return
+		}
+		list := corev1.List[corev1.Endpoints]{
+			ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+			Items: []corev1.Endpoints{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "endpoint1", ResourceVersion: "1"}},
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "endpoint2", ResourceVersion: "1"}},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(list); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	api := client.NewObjectAPI[corev1.Endpoints](&testClient{apiServerURL: srv.URL})
+	informer := cache.NewSharedInformer[corev1.Endpoints](api, "test", metav1.ListOptions{})
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	reconciler := ReconcilerFunc(func(ctx context.Context, namespace, name string) (Result, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[name]++
+		switch {
+		case name == "endpoint1" && calls[name] == 1:
+			return Result{}, errors.New("temporary error")
+		case name == "endpoint2" && calls[name] == 1:
+			return Result{RequeueAfter: 10 * time.Millisecond}, nil
+		}
+		return Result{}, nil
+	})
+
+	c := New[corev1.Endpoints](informer, reconciler,
+		WithWorkers(2),
+		WithRateLimiter(workqueue.NewItemExponentialFailureRateLimiter[Request](time.Millisecond, 10*time.Millisecond)),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- c.Run(ctx)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		done := calls["endpoint1"] == 2 && calls["endpoint2"] == 2
+		mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for reconciles, got %v", calls)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-errc; err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls["endpoint1"] != 2 || calls["endpoint2"] != 2 {
+		t.Fatalf("expected exactly 2 reconciles per object, got %v", calls)
+	}
+}
+
+type testClient struct {
+	apiServerURL string
+}
+
+func (c *testClient) Do(req *http.Request) (*http.Response, error) {
+	return http.DefaultClient.Do(req)
+}
+
+func (c *testClient) Token() string {
+	return ""
+}
+
+func (c *testClient) APIServerURL() string {
+	return c.apiServerURL
+}
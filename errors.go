@@ -0,0 +1,194 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+// StatusError is an error returned by API server for non-2xx responses.
+type StatusError struct {
+	ErrStatus metav1.Status
+}
+
+// Error implements error interface.
+func (e *StatusError) Error() string {
+	return e.ErrStatus.Message
+}
+
+// Status returns API server status.
+func (e *StatusError) Status() metav1.Status {
+	return e.ErrStatus
+}
+
+// newStatusError creates StatusError from API server response body. If body is not a Status object,
+// status is derived from HTTP response code.
+func newStatusError(decodeFunc ResponseDecoderFunc, code int, reqURL string, body []byte) *StatusError {
+	var status metav1.Status
+	if err := decodeFunc(bytes.NewReader(body)).Decode(&status); err == nil && status.Kind == "Status" {
+		if status.Code == 0 {
+			status.Code = int32(code)
+		}
+		if status.Reason == metav1.StatusReasonUnknown {
+			status.Reason = reasonForCode(code)
+		}
+		return &StatusError{ErrStatus: status}
+	}
+	return &StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    int32(code),
+		Reason:  reasonForCode(code),
+		Message: fmt.Sprintf("invalid response code %d for request url %q: %s", code, reqURL, body),
+	}}
+}
+
+// NewNotFound returns a new error which indicates that the resource of the kind and the name was not found.
+func NewNotFound(gvr metav1.GroupVersionResource, name string) *StatusError {
+	return &StatusError{ErrStatus: metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   http.StatusNotFound,
+		Reason: metav1.StatusReasonNotFound,
+		Details: &metav1.StatusDetails{
+			Group: gvr.Group,
+			Kind:  gvr.Resource,
+			Name:  name,
+		},
+		Message: fmt.Sprintf("%s %q not found", gvr.Resource, name),
+	}}
+}
+
+func reasonForCode(code int) metav1.StatusReason {
+	switch code {
+	case http.StatusBadRequest:
+		return metav1.StatusReasonBadRequest
+	case http.StatusUnauthorized:
+		return metav1.StatusReasonUnauthorized
+	case http.StatusForbidden:
+		return metav1.StatusReasonForbidden
+	case http.StatusNotFound:
+		return metav1.StatusReasonNotFound
+	case http.StatusMethodNotAllowed:
+		return metav1.StatusReasonMethodNotAllowed
+	case http.StatusConflict:
+		return metav1.StatusReasonConflict
+	case http.StatusGone:
+		return metav1.StatusReasonGone
+	case http.StatusUnprocessableEntity:
+		return metav1.StatusReasonInvalid
+	case http.StatusTooManyRequests:
+		return metav1.StatusReasonTooManyRequests
+	case http.StatusInternalServerError:
+		return metav1.StatusReasonInternalError
+	case http.StatusServiceUnavailable:
+		return metav1.StatusReasonServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return metav1.StatusReasonTimeout
+	}
+	return metav1.StatusReasonUnknown
+}
+
+// ReasonForError returns status reason for given error. Unknown reason is returned for non StatusError errors.
+func ReasonForError(err error) metav1.StatusReason {
+	if status, ok := statusForError(err); ok {
+		return status.Reason
+	}
+	return metav1.StatusReasonUnknown
+}
+
+func statusForError(err error) (metav1.Status, bool) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.ErrStatus, true
+	}
+	return metav1.Status{}, false
+}
+
+func reasonAndCodeForError(err error) (metav1.StatusReason, int32) {
+	status, _ := statusForError(err)
+	return status.Reason, status.Code
+}
+
+// IsNotFound determines if the err is an error which indicates that the requested resource was not found.
+func IsNotFound(err error) bool {
+	reason, code := reasonAndCodeForError(err)
+	return reason == metav1.StatusReasonNotFound || (reason == metav1.StatusReasonUnknown && code == http.StatusNotFound)
+}
+
+// IsAlreadyExists determines if the err is an error which indicates that a specified resource already exists.
+func IsAlreadyExists(err error) bool {
+	return ReasonForError(err) == metav1.StatusReasonAlreadyExists
+}
+
+// IsConflict determines if the err is an error which indicates the provided update conflicts.
+func IsConflict(err error) bool {
+	reason, code := reasonAndCodeForError(err)
+	return reason == metav1.StatusReasonConflict || (reason == metav1.StatusReasonUnknown && code == http.StatusConflict)
+}
+
+// IsGone is true if the error indicates the requested resource is no longer available.
+// It includes expired resource versions.
+func IsGone(err error) bool {
+	reason, code := reasonAndCodeForError(err)
+	return reason == metav1.StatusReasonGone || reason == metav1.StatusReasonExpired || code == http.StatusGone
+}
+
+// IsResourceExpired is true if the error indicates the resource has expired and the current action is
+// no longer possible.
+func IsResourceExpired(err error) bool {
+	return ReasonForError(err) == metav1.StatusReasonExpired
+}
+
+// IsInvalid determines if the err is an error which indicates the provided resource is not valid.
+func IsInvalid(err error) bool {
+	reason, code := reasonAndCodeForError(err)
+	return reason == metav1.StatusReasonInvalid || (reason == metav1.StatusReasonUnknown && code == http.StatusUnprocessableEntity)
+}
+
+// IsBadRequest determines if err is an error which indicates that the request is invalid.
+func IsBadRequest(err error) bool {
+	reason, code := reasonAndCodeForError(err)
+	return reason == metav1.StatusReasonBadRequest || (reason == metav1.StatusReasonUnknown && code == http.StatusBadRequest)
+}
+
+// IsUnauthorized determines if err is an error which indicates that the request is unauthorized and
+// requires authentication by the user.
+func IsUnauthorized(err error) bool {
+	reason, code := reasonAndCodeForError(err)
+	return reason == metav1.StatusReasonUnauthorized || (reason == metav1.StatusReasonUnknown && code == http.StatusUnauthorized)
+}
+
+// IsForbidden determines if err is an error which indicates that the request is forbidden and cannot
+// be completed as requested.
+func IsForbidden(err error) bool {
+	reason, code := reasonAndCodeForError(err)
+	return reason == metav1.StatusReasonForbidden || (reason == metav1.StatusReasonUnknown && code == http.StatusForbidden)
+}
+
+// IsTimeout determines if err is an error which indicates that request times out due to long
+// processing.
+func IsTimeout(err error) bool {
+	reason, code := reasonAndCodeForError(err)
+	return reason == metav1.StatusReasonTimeout || (reason == metav1.StatusReasonUnknown && code == http.StatusGatewayTimeout)
+}
+
+// IsServerTimeout determines if err is an error which indicates that the request needs to be retried
+// by the client.
+func IsServerTimeout(err error) bool {
+	return ReasonForError(err) == metav1.StatusReasonServerTimeout
+}
+
+// IsTooManyRequests determines if err is an error which indicates that there are too many requests
+// that the server cannot handle.
+func IsTooManyRequests(err error) bool {
+	reason, code := reasonAndCodeForError(err)
+	return reason == metav1.StatusReasonTooManyRequests || code == http.StatusTooManyRequests
+}
+
+// IsServiceUnavailable is true if the error indicates the underlying service is no longer available.
+func IsServiceUnavailable(err error) bool {
+	reason, code := reasonAndCodeForError(err)
+	return reason == metav1.StatusReasonServiceUnavailable || (reason == metav1.StatusReasonUnknown && code == http.StatusServiceUnavailable)
+}
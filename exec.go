@@ -0,0 +1,212 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const (
+	execInfoEnv = "KUBERNETES_EXEC_INFO"
+
+	execCredentialAPIVersionV1      = "client.authentication.k8s.io/v1"
+	execCredentialAPIVersionV1beta1 = "client.authentication.k8s.io/v1beta1"
+
+	// execTimeout limits plugin run time, so a hanging plugin doesn't block requests forever.
+	execTimeout = time.Minute
+)
+
+// kubeExecConfig is kubeconfig exec credential plugin configuration.
+type kubeExecConfig struct {
+	Command            string           `yaml:"command"`
+	Args               []string         `yaml:"args"`
+	Env                []kubeExecEnvVar `yaml:"env"`
+	APIVersion         string           `yaml:"apiVersion"`
+	InstallHint        string           `yaml:"installHint"`
+	ProvideClusterInfo bool             `yaml:"provideClusterInfo"`
+}
+
+type kubeExecEnvVar struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// execCredential is ExecCredential object passed to and returned from plugin.
+type execCredential struct {
+	APIVersion string                `json:"apiVersion"`
+	Kind       string                `json:"kind"`
+	Spec       execCredentialSpec    `json:"spec"`
+	Status     *execCredentialStatus `json:"status,omitempty"`
+}
+
+type execCredentialSpec struct {
+	Cluster     *execCluster `json:"cluster,omitempty"`
+	Interactive bool         `json:"interactive"`
+}
+
+type execCluster struct {
+	Server                   string `json:"server"`
+	TLSServerName            string `json:"tls-server-name,omitempty"`
+	InsecureSkipTLSVerify    bool   `json:"insecure-skip-tls-verify,omitempty"`
+	CertificateAuthorityData []byte `json:"certificate-authority-data,omitempty"`
+	ProxyURL                 string `json:"proxy-url,omitempty"`
+}
+
+type execCredentialStatus struct {
+	ExpirationTimestamp *time.Time `json:"expirationTimestamp,omitempty"`
+	Token               string     `json:"token,omitempty"`
+}
+
+// execTokenProvider runs exec credential plugin and caches returned token until it expires.
+type execTokenProvider struct {
+	cfg     kubeExecConfig
+	cluster *execCluster
+	timeout time.Duration
+
+	mu      sync.Mutex
+	token   string
+	expiry  time.Time
+	refresh *execRefresh
+}
+
+// execRefresh is a plugin run shared by all callers waiting for a new token.
+type execRefresh struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+var _ TokenSource = (*execTokenProvider)(nil)
+
+func newExecTokenProvider(cfg kubeExecConfig, cluster *execCluster) (*execTokenProvider, error) {
+	if cfg.Command == "" {
+		return nil, fmt.Errorf("exec command is not set")
+	}
+	switch cfg.APIVersion {
+	case execCredentialAPIVersionV1, execCredentialAPIVersionV1beta1:
+	default:
+		return nil, fmt.Errorf("exec plugin: unsupported apiVersion %q", cfg.APIVersion)
+	}
+	if !cfg.ProvideClusterInfo {
+		cluster = nil
+	}
+	return &execTokenProvider{cfg: cfg, cluster: cluster, timeout: execTimeout}, nil
+}
+
+// Token returns cached token or runs the plugin if token is not set or expired.
+func (p *execTokenProvider) Token() (string, error) {
+	return p.tokenContext(context.Background())
+}
+
+// tokenContext is Token which stops waiting for the plugin when context is done. Concurrent callers
+// share a single plugin run, which is not cancelled when some of them stop waiting.
+func (p *execTokenProvider) tokenContext(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	if p.token != "" && (p.expiry.IsZero() || time.Now().Before(p.expiry)) {
+		token := p.token
+		p.mu.Unlock()
+		return token, nil
+	}
+	r := p.refresh
+	if r == nil {
+		r = &execRefresh{done: make(chan struct{})}
+		p.refresh = r
+		go p.run(r)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.token, r.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Invalidate drops cached token if it's still the given one, so the next Token call runs the plugin again.
+func (p *execTokenProvider) Invalidate(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token == token {
+		p.token = ""
+	}
+}
+
+func (p *execTokenProvider) run(r *execRefresh) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	status, err := p.exec(ctx)
+
+	p.mu.Lock()
+	if err == nil {
+		p.token = status.Token
+		p.expiry = time.Time{}
+		if status.ExpirationTimestamp != nil {
+			p.expiry = *status.ExpirationTimestamp
+		}
+		r.token = status.Token
+	}
+	r.err = err
+	p.refresh = nil
+	p.mu.Unlock()
+	close(r.done)
+}
+
+func (p *execTokenProvider) exec(ctx context.Context) (*execCredentialStatus, error) {
+	info, err := json.Marshal(execCredential{
+		APIVersion: p.cfg.APIVersion,
+		Kind:       "ExecCredential",
+		Spec:       execCredentialSpec{Cluster: p.cluster},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, p.cfg.Command, p.cfg.Args...)
+	cmd.Env = append(os.Environ(), execInfoEnv+"="+string(info))
+	for _, env := range p.cfg.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		if _, ok := err.(*exec.Error); ok && p.cfg.InstallHint != "" {
+			return nil, fmt.Errorf("exec plugin: %w\n\n%s", err, p.cfg.InstallHint)
+		}
+		return nil, fmt.Errorf("exec plugin: %w", err)
+	}
+	// Wait may not return after the plugin is killed while its child processes keep output open,
+	// so timeout is not left to Wait.
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- cmd.Wait()
+	}()
+	select {
+	case err = <-waitErr:
+	case <-ctx.Done():
+	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("exec plugin: timed out after %v: %w", p.timeout, ctx.Err())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("exec plugin: %w", err)
+	}
+
+	var cred execCredential
+	if err := json.Unmarshal(stdout.Bytes(), &cred); err != nil {
+		return nil, fmt.Errorf("exec plugin: decoding ExecCredential: %w", err)
+	}
+	if cred.APIVersion != p.cfg.APIVersion {
+		return nil, fmt.Errorf("exec plugin: expected ExecCredential apiVersion %q, got %q", p.cfg.APIVersion, cred.APIVersion)
+	}
+	if cred.Status == nil || cred.Status.Token == "" {
+		return nil, fmt.Errorf("exec plugin: ExecCredential status does not contain token")
+	}
+	return cred.Status, nil
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+func TestExecCredentialPlugin(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// First issued token is revoked.
+		if auth := r.Header.Get("Authorization"); auth != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		endpoints := corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "endpoint1", Namespace: "test"}}
+		if err := json.NewEncoder(w).Encode(endpoints); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	// Plugin counts its executions and returns token with execution number.
+	writeFile(t, filepath.Join(dir, "plugin.sh"), `#!/bin/sh
+printf x >> "$(dirname "$0")/count"
+n=$(wc -c < "$(dirname "$0")/count" | tr -d ' ')
+cat <<EOF
+{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"token-$n","expirationTimestamp":"2999-01-01T00:00:00Z"}}
+EOF
+`)
+	if err := os.Chmod(filepath.Join(dir, "plugin.sh"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "config"), `
+current-context: test
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+clusters:
+- name: test
+  cluster:
+    server: `+srv.URL+`
+users:
+- name: test
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1
+      command: ./plugin.sh
+      provideClusterInfo: true
+`)
+
+	kc, err := NewFromKubeconfig(filepath.Join(dir, "config"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := NewObjectAPI[corev1.Endpoints](kc)
+	for i := 0; i < 2; i++ {
+		if _, err := api.Get(context.Background(), "test", "endpoint1", metav1.GetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Plugin is executed again only after 401, valid token is cached.
+	count, err := ioutil.ReadFile(filepath.Join(dir, "count"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if execs := strings.Count(string(count), "x"); execs != 2 {
+		t.Fatalf("expected plugin to be executed 2 times, got %d", execs)
+	}
+	if token := kc.Token(); token != "token-2" {
+		t.Fatalf("expected cached token %q, got %q", "token-2", token)
+	}
+}
+
+func TestExecCredentialPluginTimeout(t *testing.T) {
+	dir := t.TempDir()
+	// Plugin counts its executions and never exits.
+	writeFile(t, filepath.Join(dir, "plugin.sh"), `#!/bin/sh
+printf x >> "$(dirname "$0")/count"
+exec sleep 1000
+`)
+	if err := os.Chmod(filepath.Join(dir, "plugin.sh"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "config"), `
+current-context: test
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:1
+users:
+- name: test
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1
+      command: ./plugin.sh
+`)
+	kc, err := NewFromKubeconfig(filepath.Join(dir, "config"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := kc.tokenSource.(*execTokenProvider)
+	p.timeout = 2 * time.Second
+
+	// Request stops waiting for token when its context is done, plugin keeps running.
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	api := NewObjectAPI[corev1.Endpoints](kc)
+	if _, err := api.Get(ctx, "test", "endpoint1", metav1.GetOptions{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected request to return when context is done, waited %v", elapsed)
+	}
+
+	// Concurrent caller waits for the running plugin, which is killed after timeout.
+	if _, err := p.Token(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	count, err := ioutil.ReadFile(filepath.Join(dir, "count"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if execs := strings.Count(string(count), "x"); execs != 1 {
+		t.Fatalf("expected plugin to be executed once, got %d", execs)
+	}
+}
@@ -0,0 +1,95 @@
+// Package fields implements field selectors. It's adapted from k8s.io/apimachinery/pkg/fields.
+package fields
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+// Fields allows you to present fields independently of their storage.
+type Fields interface {
+	// Has returns whether the provided field exists.
+	Has(field string) bool
+	// Get returns the value for the provided field.
+	Get(field string) string
+}
+
+// Set is a map of field:value. It implements Fields.
+type Set map[string]string
+
+// Has returns whether the provided field exists in the map.
+func (ls Set) Has(field string) bool {
+	_, exists := ls[field]
+	return exists
+}
+
+// Get returns the value in the map for the provided field.
+func (ls Set) Get(field string) string {
+	return ls[field]
+}
+
+// String returns all fields listed as a human readable string.
+// Conveniently, exactly the format that ParseSelector takes.
+func (ls Set) String() string {
+	selector := make([]string, 0, len(ls))
+	for key, value := range ls {
+		selector = append(selector, key+"="+EscapeValue(value))
+	}
+	// Sort for determinism.
+	sort.StringSlice(selector).Sort()
+	return strings.Join(selector, ",")
+}
+
+// AsSelector converts fields into a selector which matches all of them.
+func (ls Set) AsSelector() Selector {
+	return SelectorFromSet(ls)
+}
+
+// FieldsFunc returns fields of an object which field selectors are evaluated against.
+// Each object type defines its own accessor, e.g. pods expose spec.nodeName and status.phase.
+type FieldsFunc[T corev1.Object] func(obj *T) Set
+
+// ObjectMetaFieldsSet returns fields supported by all objects: metadata.name and, for namespaced
+// objects, metadata.namespace.
+func ObjectMetaFieldsSet(meta metav1.ObjectMeta, hasNamespaceField bool) Set {
+	if !hasNamespaceField {
+		return Set{"metadata.name": meta.Name}
+	}
+	return Set{
+		"metadata.name":      meta.Name,
+		"metadata.namespace": meta.Namespace,
+	}
+}
+
+// ObjectMetaFields is FieldsFunc for namespaced objects which support only metadata fields.
+func ObjectMetaFields[T corev1.Object](obj *T) Set {
+	return ObjectMetaFieldsSet((*obj).GetObjectMeta(), true)
+}
+
+// MergeFieldsSets merges fields sets, values from later sets take precedence.
+func MergeFieldsSets(sets ...Set) Set {
+	res := Set{}
+	for _, set := range sets {
+		for k, v := range set {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// Filter returns objects matching selector, fields of each object are taken from fieldsFunc.
+func Filter[T corev1.Object](objs []*T, selector Selector, fieldsFunc FieldsFunc[T]) []*T {
+	if selector == nil || selector.Empty() {
+		return objs
+	}
+	res := make([]*T, 0, len(objs))
+	for _, obj := range objs {
+		if selector.Matches(fieldsFunc(obj)) {
+			res = append(res, obj)
+		}
+	}
+	return res
+}
@@ -0,0 +1,273 @@
+package fields
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Selector represents a field selector.
+type Selector interface {
+	// Matches returns true if this selector matches the given set of fields.
+	Matches(Fields) bool
+	// Empty returns true if this selector does not restrict the selection space.
+	Empty() bool
+	// RequiresExactMatch allows a caller to introspect whether a given selector
+	// requires a single specific field to be set, and if so returns the value it
+	// requires.
+	RequiresExactMatch(field string) (value string, found bool)
+	// Requirements converts this selector into requirements.
+	Requirements() Requirements
+	// String returns a human readable string that represents this selector.
+	// It is in the format accepted by ParseSelector and API server fieldSelector query parameter.
+	String() string
+}
+
+// Operator is a field selector requirement operator.
+type Operator string
+
+const (
+	Equals       Operator = "="
+	DoubleEquals Operator = "=="
+	NotEquals    Operator = "!="
+)
+
+// Requirement contains a field, a value, and an operator that relates the field and value.
+type Requirement struct {
+	Operator Operator
+	Field    string
+	Value    string
+}
+
+// Requirements is AND of all requirements.
+type Requirements []Requirement
+
+// Matches returns true if the Requirement matches the input Fields.
+func (r Requirement) Matches(ls Fields) bool {
+	switch r.Operator {
+	case Equals, DoubleEquals:
+		return ls.Get(r.Field) == r.Value
+	case NotEquals:
+		return ls.Get(r.Field) != r.Value
+	default:
+		return false
+	}
+}
+
+// String returns a human-readable string that represents this Requirement.
+func (r Requirement) String() string {
+	return r.Field + string(r.Operator) + EscapeValue(r.Value)
+}
+
+// Everything returns a selector that matches all fields.
+func Everything() Selector {
+	return andTerm{}
+}
+
+// SelectorFromSet returns a Selector which will match exactly the given Set.
+func SelectorFromSet(ls Set) Selector {
+	items := make(andTerm, 0, len(ls))
+	for field, value := range ls {
+		items = append(items, Requirement{Operator: Equals, Field: field, Value: value})
+	}
+	sort.Sort(byField(items))
+	return items
+}
+
+// OneTermEqualSelector returns an object that matches objects where one field/field equals one value.
+func OneTermEqualSelector(k, v string) Selector {
+	return andTerm{{Operator: Equals, Field: k, Value: v}}
+}
+
+// OneTermNotEqualSelector returns an object that matches objects where one field/field does not equal one value.
+func OneTermNotEqualSelector(k, v string) Selector {
+	return andTerm{{Operator: NotEquals, Field: k, Value: v}}
+}
+
+// AndSelectors creates a selector that is the logical AND of all the given selectors.
+func AndSelectors(selectors ...Selector) Selector {
+	var items andTerm
+	for _, s := range selectors {
+		items = append(items, s.Requirements()...)
+	}
+	return items
+}
+
+// andTerm is AND of all requirements.
+type andTerm []Requirement
+
+type byField []Requirement
+
+func (a byField) Len() int           { return len(a) }
+func (a byField) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byField) Less(i, j int) bool { return a[i].Field < a[j].Field }
+
+func (t andTerm) Matches(ls Fields) bool {
+	for _, r := range t {
+		if !r.Matches(ls) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t andTerm) Empty() bool {
+	return len(t) == 0
+}
+
+func (t andTerm) RequiresExactMatch(field string) (string, bool) {
+	for _, r := range t {
+		if r.Field == field && (r.Operator == Equals || r.Operator == DoubleEquals) {
+			return r.Value, true
+		}
+	}
+	return "", false
+}
+
+func (t andTerm) Requirements() Requirements {
+	return Requirements(t)
+}
+
+func (t andTerm) String() string {
+	terms := make([]string, 0, len(t))
+	for _, r := range t {
+		terms = append(terms, r.String())
+	}
+	return strings.Join(terms, ",")
+}
+
+// ParseSelector takes a string representing a selector and returns an
+// object suitable for matching, or an error. Terms are separated by comma and
+// use one of =, == or != operators, e.g. "spec.nodeName=foo,status.phase!=Running".
+// Special characters in values must be escaped with EscapeValue.
+func ParseSelector(selector string) (Selector, error) {
+	var items andTerm
+	for _, term := range splitTerms(selector) {
+		if term == "" {
+			continue
+		}
+		field, op, value, ok := splitTerm(term)
+		if !ok {
+			return nil, fmt.Errorf("invalid selector: '%s'; can't understand '%s'", selector, term)
+		}
+		if field == "" {
+			return nil, fmt.Errorf("invalid selector: '%s'; field name is empty in '%s'", selector, term)
+		}
+		unescaped, err := UnescapeValue(value)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, Requirement{Operator: op, Field: field, Value: unescaped})
+	}
+	return items, nil
+}
+
+// ParseSelectorOrDie takes a string representing a selector and returns an
+// object suitable for matching, or panics when an error occur.
+func ParseSelectorOrDie(s string) Selector {
+	selector, err := ParseSelector(s)
+	if err != nil {
+		panic(err)
+	}
+	return selector
+}
+
+var valueEscaper = strings.NewReplacer(
+	// escape \ characters
+	`\`, `\\`,
+	// then escape , and = characters to allow unambiguous parsing of the value in a fieldSelector
+	`,`, `\,`,
+	`=`, `\=`,
+)
+
+// EscapeValue escapes an arbitrary literal string for use as a fieldSelector value.
+func EscapeValue(s string) string {
+	return valueEscaper.Replace(s)
+}
+
+// UnescapeValue reverts EscapeValue. Error is returned for unknown escape sequences and unescaped
+// separators.
+func UnescapeValue(s string) (string, error) {
+	// Value without special characters is returned as is.
+	if !strings.ContainsAny(s, `\,=`) {
+		return s, nil
+	}
+
+	v := strings.Builder{}
+	inSlash := false
+	for _, c := range s {
+		if inSlash {
+			switch c {
+			case '\\', ',', '=':
+				// Escaped character is written without backslash.
+				v.WriteRune(c)
+			default:
+				return "", fmt.Errorf("invalid field selector: invalid escape sequence: %s", `\`+string(c))
+			}
+			inSlash = false
+			continue
+		}
+
+		switch c {
+		case '\\':
+			inSlash = true
+		case ',', '=':
+			// Separators must be escaped in values.
+			return "", fmt.Errorf("invalid field selector: unescaped character in value: %s", string(c))
+		default:
+			v.WriteRune(c)
+		}
+	}
+
+	// Trailing backslash doesn't escape anything.
+	if inSlash {
+		return "", fmt.Errorf("invalid field selector: unescaped character in value: %s", `\`)
+	}
+
+	return v.String(), nil
+}
+
+// splitTerms returns the comma-separated terms contained in the given fieldSelector.
+// Backslash-escaped commas are treated as data instead of delimiters, and are included in the returned terms,
+// with the leading backslash preserved.
+func splitTerms(fieldSelector string) []string {
+	if len(fieldSelector) == 0 {
+		return nil
+	}
+
+	terms := make([]string, 0, 1)
+	startIndex := 0
+	inSlash := false
+	for i, c := range fieldSelector {
+		switch {
+		case inSlash:
+			inSlash = false
+		case c == '\\':
+			inSlash = true
+		case c == ',':
+			terms = append(terms, fieldSelector[startIndex:i])
+			startIndex = i + 1
+		}
+	}
+
+	terms = append(terms, fieldSelector[startIndex:])
+
+	return terms
+}
+
+// termOperators lists operators in the order they are matched. Equals goes last, as it's a prefix of DoubleEquals.
+var termOperators = []Operator{NotEquals, DoubleEquals, Equals}
+
+// splitTerm splits term at the first operator into field, operator and value. Field can't contain escaped
+// characters, value is returned escaped.
+func splitTerm(term string) (lhs string, op Operator, rhs string, ok bool) {
+	for i := range term {
+		remaining := term[i:]
+		for _, op := range termOperators {
+			if strings.HasPrefix(remaining, string(op)) {
+				return term[0:i], op, term[i+len(op):], true
+			}
+		}
+	}
+	return "", "", "", false
+}
@@ -0,0 +1,77 @@
+package fields
+
+import (
+	"testing"
+
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+func TestParseSelector(t *testing.T) {
+	s, err := ParseSelector("spec.nodeName=node1,status.phase!=Running")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "spec.nodeName=node1,status.phase!=Running"; s.String() != expected {
+		t.Fatalf("expected string %q, got %q", expected, s.String())
+	}
+	if value, ok := s.RequiresExactMatch("spec.nodeName"); !ok || value != "node1" {
+		t.Fatalf("expected exact match on node name, got %q %v", value, ok)
+	}
+	if !s.Matches(Set{"spec.nodeName": "node1", "status.phase": "Pending"}) {
+		t.Fatal("expected selector to match")
+	}
+	if s.Matches(Set{"spec.nodeName": "node1", "status.phase": "Running"}) {
+		t.Fatal("expected selector not to match")
+	}
+
+	escaped, err := ParseSelector(`metadata.name=a\,b\=c`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !escaped.Matches(Set{"metadata.name": "a,b=c"}) {
+		t.Fatal("expected escaped selector to match")
+	}
+	if escaped.String() != `metadata.name=a\,b\=c` {
+		t.Fatalf("unexpected escaped string %q", escaped.String())
+	}
+
+	for _, selector := range []string{"spec.nodeName", "=node1", `a=b\c`, "a=b=c"} {
+		if _, err := ParseSelector(selector); err == nil {
+			t.Fatalf("expected error for selector %q", selector)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	pods := []*testPod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}, NodeName: "node1"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod2"}, NodeName: "node2"},
+	}
+	selector := AndSelectors(OneTermEqualSelector("spec.nodeName", "node1"), Everything())
+	res := Filter(pods, selector, testPodFields)
+	if len(res) != 1 || res[0].Name != "pod1" {
+		t.Fatalf("unexpected filtered pods %v", res)
+	}
+}
+
+type testPod struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	NodeName          string
+}
+
+func (p testPod) GetObjectMeta() metav1.ObjectMeta {
+	return p.ObjectMeta
+}
+
+func (p testPod) GetTypeMeta() metav1.TypeMeta {
+	return p.TypeMeta
+}
+
+func (p testPod) GVR() metav1.GroupVersionResource {
+	return metav1.GroupVersionResource{Version: "v1", Resource: "pods"}
+}
+
+func testPodFields(p *testPod) Set {
+	return MergeFieldsSets(ObjectMetaFields(p), Set{"spec.nodeName": p.NodeName})
+}
@@ -2,6 +2,9 @@ module github.com/castai/k8s-client-go
 
 go 1.18
 
-require github.com/fsnotify/fsnotify v1.5.4
+require (
+	github.com/fsnotify/fsnotify v1.5.4
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require golang.org/x/sys v0.0.0-20220412211240-33da011f77ad // indirect
@@ -0,0 +1,308 @@
+package client
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// RecommendedConfigPathEnvVar is environment variable with list of kubeconfig files.
+	RecommendedConfigPathEnvVar = "KUBECONFIG"
+)
+
+// kubeConfig is a subset of kubeconfig file format needed to build a client.
+type kubeConfig struct {
+	Clusters       []namedKubeCluster  `yaml:"clusters"`
+	Users          []namedKubeAuthInfo `yaml:"users"`
+	Contexts       []namedKubeContext  `yaml:"contexts"`
+	CurrentContext string              `yaml:"current-context"`
+}
+
+type namedKubeCluster struct {
+	Name    string      `yaml:"name"`
+	Cluster kubeCluster `yaml:"cluster"`
+}
+
+type kubeCluster struct {
+	Server                   string `yaml:"server"`
+	TLSServerName            string `yaml:"tls-server-name"`
+	InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+	CertificateAuthority     string `yaml:"certificate-authority"`
+	CertificateAuthorityData string `yaml:"certificate-authority-data"`
+	ProxyURL                 string `yaml:"proxy-url"`
+}
+
+type namedKubeAuthInfo struct {
+	Name     string       `yaml:"name"`
+	AuthInfo kubeAuthInfo `yaml:"user"`
+}
+
+type kubeAuthInfo struct {
+	Token                 string          `yaml:"token"`
+	TokenFile             string          `yaml:"tokenFile"`
+	ClientCertificate     string          `yaml:"client-certificate"`
+	ClientCertificateData string          `yaml:"client-certificate-data"`
+	ClientKey             string          `yaml:"client-key"`
+	ClientKeyData         string          `yaml:"client-key-data"`
+	Username              string          `yaml:"username"`
+	Password              string          `yaml:"password"`
+	Exec                  *kubeExecConfig `yaml:"exec"`
+	AuthProvider          yaml.Node       `yaml:"auth-provider"`
+}
+
+type namedKubeContext struct {
+	Name    string      `yaml:"name"`
+	Context kubeContext `yaml:"context"`
+}
+
+type kubeContext struct {
+	Cluster   string `yaml:"cluster"`
+	AuthInfo  string `yaml:"user"`
+	Namespace string `yaml:"namespace"`
+}
+
+// NewFromKubeconfig creates Client from kubeconfig file. If path is empty, files listed in KUBECONFIG
+// environment variable are merged, or ~/.kube/config is used if it's not set. If contextName is empty,
+// current-context is used.
+func NewFromKubeconfig(path, contextName string) (*DefaultClient, error) {
+	cfg, err := ConfigFromKubeconfig(path, contextName)
+	if err != nil {
+		return nil, err
+	}
+	return NewForConfig(cfg)
+}
+
+// ConfigFromKubeconfig returns Config for the context of kubeconfig file. See NewFromKubeconfig for
+// path and contextName handling.
+func ConfigFromKubeconfig(path, contextName string) (*Config, error) {
+	paths, err := kubeconfigPaths(path)
+	if err != nil {
+		return nil, err
+	}
+	kcfg, err := loadKubeconfig(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	if contextName == "" {
+		contextName = kcfg.CurrentContext
+	}
+	if contextName == "" {
+		return nil, fmt.Errorf("context is not specified and current-context is not set in kubeconfig")
+	}
+	kctx, ok := findKubeContext(kcfg.Contexts, contextName)
+	if !ok {
+		return nil, fmt.Errorf("context %q not found in kubeconfig", contextName)
+	}
+	cluster, ok := findKubeCluster(kcfg.Clusters, kctx.Cluster)
+	if !ok {
+		return nil, fmt.Errorf("cluster %q of context %q not found in kubeconfig", kctx.Cluster, contextName)
+	}
+	authInfo, ok := findKubeAuthInfo(kcfg.Users, kctx.AuthInfo)
+	if !ok && kctx.AuthInfo != "" {
+		return nil, fmt.Errorf("user %q of context %q not found in kubeconfig", kctx.AuthInfo, contextName)
+	}
+
+	if cluster.Server == "" {
+		return nil, fmt.Errorf("server of cluster %q is not set in kubeconfig", kctx.Cluster)
+	}
+	cfg := &Config{
+		Host:          cluster.Server,
+		TLSServerName: cluster.TLSServerName,
+		Insecure:      cluster.InsecureSkipTLSVerify,
+	}
+	if err := applyKubeCluster(cfg, cluster); err != nil {
+		return nil, fmt.Errorf("cluster %q: %w", kctx.Cluster, err)
+	}
+	if err := applyKubeAuthInfo(cfg, authInfo); err != nil {
+		return nil, fmt.Errorf("user %q: %w", kctx.AuthInfo, err)
+	}
+	if authInfo.Exec != nil {
+		cfg.TokenSource, err = newExecTokenProvider(*authInfo.Exec, &execCluster{
+			Server:                   cluster.Server,
+			TLSServerName:            cluster.TLSServerName,
+			InsecureSkipTLSVerify:    cluster.InsecureSkipTLSVerify,
+			CertificateAuthorityData: cfg.CAData,
+			ProxyURL:                 cluster.ProxyURL,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("user %q: %w", kctx.AuthInfo, err)
+		}
+	}
+	return cfg, nil
+}
+
+// kubeconfigPaths returns list of kubeconfig files to merge.
+func kubeconfigPaths(path string) ([]string, error) {
+	if path != "" {
+		return []string{path}, nil
+	}
+	if env := os.Getenv(RecommendedConfigPathEnvVar); env != "" {
+		var paths []string
+		for _, p := range filepath.SplitList(env) {
+			if p != "" {
+				paths = append(paths, p)
+			}
+		}
+		return paths, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("unable to find default kubeconfig: %w", err)
+	}
+	return []string{filepath.Join(home, ".kube", "config")}, nil
+}
+
+// loadKubeconfig reads and merges kubeconfig files. As in kubectl, the first file to set a value wins,
+// missing files listed in KUBECONFIG are ignored, relative file references are resolved against
+// the directory of the file they are defined in.
+func loadKubeconfig(paths []string) (*kubeConfig, error) {
+	merged := &kubeConfig{}
+	loaded := 0
+	for _, p := range paths {
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			if os.IsNotExist(err) && len(paths) > 1 {
+				continue
+			}
+			return nil, fmt.Errorf("reading kubeconfig: %w", err)
+		}
+		var cfg kubeConfig
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parsing kubeconfig %q: %w", p, err)
+		}
+		loaded++
+		resolveKubeconfigPaths(&cfg, filepath.Dir(p))
+
+		if merged.CurrentContext == "" {
+			merged.CurrentContext = cfg.CurrentContext
+		}
+		for _, c := range cfg.Clusters {
+			if _, ok := findKubeCluster(merged.Clusters, c.Name); !ok {
+				merged.Clusters = append(merged.Clusters, c)
+			}
+		}
+		for _, u := range cfg.Users {
+			if _, ok := findKubeAuthInfo(merged.Users, u.Name); !ok {
+				merged.Users = append(merged.Users, u)
+			}
+		}
+		for _, c := range cfg.Contexts {
+			if _, ok := findKubeContext(merged.Contexts, c.Name); !ok {
+				merged.Contexts = append(merged.Contexts, c)
+			}
+		}
+	}
+	if loaded == 0 {
+		return nil, fmt.Errorf("no kubeconfig files found in %v", paths)
+	}
+	return merged, nil
+}
+
+func resolveKubeconfigPaths(cfg *kubeConfig, dir string) {
+	resolve := func(p *string) {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+	for i := range cfg.Clusters {
+		resolve(&cfg.Clusters[i].Cluster.CertificateAuthority)
+	}
+	for i := range cfg.Users {
+		resolve(&cfg.Users[i].AuthInfo.TokenFile)
+		resolve(&cfg.Users[i].AuthInfo.ClientCertificate)
+		resolve(&cfg.Users[i].AuthInfo.ClientKey)
+		// Exec command is resolved only if it's a path, otherwise it's looked up in PATH.
+		if exec := cfg.Users[i].AuthInfo.Exec; exec != nil && strings.ContainsRune(exec.Command, filepath.Separator) {
+			resolve(&exec.Command)
+		}
+	}
+}
+
+func findKubeCluster(clusters []namedKubeCluster, name string) (kubeCluster, bool) {
+	for _, c := range clusters {
+		if c.Name == name {
+			return c.Cluster, true
+		}
+	}
+	return kubeCluster{}, false
+}
+
+func findKubeAuthInfo(users []namedKubeAuthInfo, name string) (kubeAuthInfo, bool) {
+	for _, u := range users {
+		if u.Name == name {
+			return u.AuthInfo, true
+		}
+	}
+	return kubeAuthInfo{}, false
+}
+
+func findKubeContext(contexts []namedKubeContext, name string) (kubeContext, bool) {
+	for _, c := range contexts {
+		if c.Name == name {
+			return c.Context, true
+		}
+	}
+	return kubeContext{}, false
+}
+
+// applyKubeCluster sets CA and proxy from kubeconfig cluster. CA file is read, so it can be passed
+// to exec plugin.
+func applyKubeCluster(cfg *Config, cluster kubeCluster) error {
+	switch {
+	case cluster.CertificateAuthorityData != "":
+		data, err := base64.StdEncoding.DecodeString(cluster.CertificateAuthorityData)
+		if err != nil {
+			return fmt.Errorf("decoding certificate-authority-data: %w", err)
+		}
+		cfg.CAData = data
+	case cluster.CertificateAuthority != "":
+		data, err := ioutil.ReadFile(cluster.CertificateAuthority)
+		if err != nil {
+			return fmt.Errorf("reading certificate-authority: %w", err)
+		}
+		cfg.CAData = data
+	}
+	if cluster.ProxyURL != "" {
+		u, err := url.Parse(cluster.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("parsing proxy-url: %w", err)
+		}
+		cfg.Proxy = http.ProxyURL(u)
+	}
+	return nil
+}
+
+// applyKubeAuthInfo sets credentials from kubeconfig user.
+func applyKubeAuthInfo(cfg *Config, authInfo kubeAuthInfo) error {
+	switch {
+	case !authInfo.AuthProvider.IsZero():
+		return fmt.Errorf("auth providers are not supported")
+	case authInfo.Username != "" || authInfo.Password != "":
+		return fmt.Errorf("basic authentication is not supported")
+	case authInfo.Exec != nil && (authInfo.Token != "" || authInfo.TokenFile != ""):
+		return fmt.Errorf("token and exec plugin can't be used together")
+	}
+	cfg.BearerToken = authInfo.Token
+	cfg.BearerTokenFile = authInfo.TokenFile
+
+	certData, err := base64.StdEncoding.DecodeString(authInfo.ClientCertificateData)
+	if err != nil {
+		return fmt.Errorf("decoding client-certificate-data: %w", err)
+	}
+	keyData, err := base64.StdEncoding.DecodeString(authInfo.ClientKeyData)
+	if err != nil {
+		return fmt.Errorf("decoding client-key-data: %w", err)
+	}
+	cfg.CertFile, cfg.KeyFile = authInfo.ClientCertificate, authInfo.ClientKey
+	cfg.CertData, cfg.KeyData = certData, keyData
+	return nil
+}
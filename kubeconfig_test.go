@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+func TestNewFromKubeconfig(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer file-token" {
+			t.Errorf("expected bearer token from token file, got %q", auth)
+		}
+		endpoints := corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "endpoint1", Namespace: "test"}}
+		if err := json.NewEncoder(w).Encode(endpoints); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	writeFile(t, filepath.Join(dir, "ca.crt"), string(ca))
+	writeFile(t, filepath.Join(dir, "token"), "file-token\n")
+
+	// First file wins for current-context and duplicated entries.
+	writeFile(t, filepath.Join(dir, "config1"), `
+apiVersion: v1
+kind: Config
+current-context: kind
+contexts:
+- name: kind
+  context:
+    cluster: kind
+    user: kind
+users:
+- name: kind
+  user:
+    tokenFile: token
+`)
+	writeFile(t, filepath.Join(dir, "config2"), `
+apiVersion: v1
+kind: Config
+current-context: other
+clusters:
+- name: kind
+  cluster:
+    server: `+srv.URL+`/
+    certificate-authority: ca.crt
+users:
+- name: kind
+  user:
+    token: ignored-token
+`)
+	t.Setenv(RecommendedConfigPathEnvVar, filepath.Join(dir, "config1")+string(os.PathListSeparator)+
+		filepath.Join(dir, "missing")+string(os.PathListSeparator)+filepath.Join(dir, "config2"))
+
+	kc, err := NewFromKubeconfig("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kc.APIServerURL() != srv.URL {
+		t.Fatalf("expected api server url %q, got %q", srv.URL, kc.APIServerURL())
+	}
+
+	api := NewObjectAPI[corev1.Endpoints](kc)
+	if _, err := api.Get(context.Background(), "test", "endpoint1", metav1.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFromKubeconfig("", "other"); err == nil {
+		t.Fatal("expected error for missing context")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,46 @@
+// Package labels implements label selectors. It's adapted from k8s.io/apimachinery/pkg/labels.
+package labels
+
+import (
+	"sort"
+	"strings"
+)
+
+// Labels allows you to present labels independently of their storage.
+type Labels interface {
+	// Has returns whether the provided label exists.
+	Has(label string) bool
+	// Get returns the value for the provided label.
+	Get(label string) string
+}
+
+// Set is a map of label:value. It implements Labels.
+type Set map[string]string
+
+// Has returns whether the provided label exists in the map.
+func (ls Set) Has(label string) bool {
+	_, exists := ls[label]
+	return exists
+}
+
+// Get returns the value in the map for the provided label.
+func (ls Set) Get(label string) string {
+	return ls[label]
+}
+
+// String returns all labels listed as a human readable string.
+// Conveniently, exactly the format that Parse takes.
+func (ls Set) String() string {
+	selector := make([]string, 0, len(ls))
+	for key, value := range ls {
+		selector = append(selector, key+"="+value)
+	}
+	// Sort for determinism.
+	sort.StringSlice(selector).Sort()
+	return strings.Join(selector, ",")
+}
+
+// AsSelector converts labels into a selector which matches all of them.
+func (ls Set) AsSelector() Selector {
+	return SelectorFromSet(ls)
+}
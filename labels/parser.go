@@ -0,0 +1,261 @@
+package labels
+
+import (
+	"fmt"
+	"sort"
+	"unicode"
+
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+type tokenType int
+
+const (
+	endOfStringToken tokenType = iota
+	identifierToken
+	commaToken
+	openParToken
+	closedParToken
+	equalsToken
+	doubleEqualsToken
+	notEqualsToken
+	bangToken
+)
+
+type token struct {
+	typ     tokenType
+	literal string
+}
+
+// lexer splits selector string into tokens.
+type lexer struct {
+	s   string
+	pos int
+}
+
+func isSpecialSymbol(ch byte) bool {
+	switch ch {
+	case '=', '!', '(', ')', ',':
+		return true
+	}
+	return false
+}
+
+func (l *lexer) next() token {
+	for l.pos < len(l.s) && unicode.IsSpace(rune(l.s[l.pos])) {
+		l.pos++
+	}
+	if l.pos >= len(l.s) {
+		return token{typ: endOfStringToken}
+	}
+	ch := l.s[l.pos]
+	if isSpecialSymbol(ch) {
+		var next byte
+		if l.pos+1 < len(l.s) {
+			next = l.s[l.pos+1]
+		}
+		switch {
+		case ch == '=' && next == '=':
+			l.pos += 2
+			return token{typ: doubleEqualsToken, literal: "=="}
+		case ch == '!' && next == '=':
+			l.pos += 2
+			return token{typ: notEqualsToken, literal: "!="}
+		}
+		l.pos++
+		switch ch {
+		case '=':
+			return token{typ: equalsToken, literal: "="}
+		case '!':
+			return token{typ: bangToken, literal: "!"}
+		case '(':
+			return token{typ: openParToken, literal: "("}
+		case ')':
+			return token{typ: closedParToken, literal: ")"}
+		default:
+			return token{typ: commaToken, literal: ","}
+		}
+	}
+	start := l.pos
+	for l.pos < len(l.s) && !isSpecialSymbol(l.s[l.pos]) && !unicode.IsSpace(rune(l.s[l.pos])) {
+		l.pos++
+	}
+	return token{typ: identifierToken, literal: l.s[start:l.pos]}
+}
+
+// parser is a recursive descent parser of selector strings.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) consume() token {
+	t := p.tokens[p.pos]
+	if t.typ != endOfStringToken {
+		p.pos++
+	}
+	return t
+}
+
+// Parse takes a string representing a selector and returns a selector object, or an error.
+// The input will cause an error if it does not follow this form:
+//
+//	<selector-syntax>         ::= <requirement> | <requirement> "," <selector-syntax>
+//	<requirement>             ::= [!] KEY [ <set-based-restriction> | <exact-match-restriction> ]
+//	<set-based-restriction>   ::= "" | <inclusion-exclusion> <value-set>
+//	<inclusion-exclusion>     ::= <inclusion> | <exclusion>
+//	<exclusion>               ::= "notin"
+//	<inclusion>               ::= "in"
+//	<value-set>               ::= "(" <values> ")"
+//	<values>                  ::= VALUE | VALUE "," <values>
+//	<exact-match-restriction> ::= ["="|"=="|"!="] VALUE
+//
+// Example of valid syntax:
+//
+//	"x in (foo,,baz),y,!z,w!=qux"
+func Parse(selector string) (Selector, error) {
+	l := &lexer{s: selector}
+	p := &parser{}
+	for {
+		t := l.next()
+		p.tokens = append(p.tokens, t)
+		if t.typ == endOfStringToken {
+			break
+		}
+	}
+
+	reqs := internalSelector{}
+	if p.peek().typ == endOfStringToken {
+		return reqs, nil
+	}
+	for {
+		r, err := p.parseRequirement()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse selector %q: %w", selector, err)
+		}
+		reqs = append(reqs, *r)
+		t := p.consume()
+		switch t.typ {
+		case endOfStringToken:
+			sort.Stable(byKey(reqs))
+			return reqs, nil
+		case commaToken:
+			if p.peek().typ == endOfStringToken {
+				return nil, fmt.Errorf("unable to parse selector %q: found trailing ','", selector)
+			}
+		default:
+			return nil, fmt.Errorf("unable to parse selector %q: found %q, expected ','", selector, t.literal)
+		}
+	}
+}
+
+func (p *parser) parseRequirement() (*Requirement, error) {
+	t := p.consume()
+	if t.typ == bangToken {
+		key := p.consume()
+		if key.typ != identifierToken {
+			return nil, fmt.Errorf("found %q, expected identifier after '!'", key.literal)
+		}
+		return NewRequirement(key.literal, DoesNotExist, nil)
+	}
+	if t.typ != identifierToken {
+		return nil, fmt.Errorf("found %q, expected '!' or identifier", t.literal)
+	}
+	key := t.literal
+
+	op := p.peek()
+	switch {
+	case op.typ == endOfStringToken || op.typ == commaToken:
+		return NewRequirement(key, Exists, nil)
+	case op.typ == identifierToken && (op.literal == string(In) || op.literal == string(NotIn)):
+		p.consume()
+		values, err := p.parseValueSet()
+		if err != nil {
+			return nil, err
+		}
+		return NewRequirement(key, Operator(op.literal), values)
+	case op.typ == equalsToken || op.typ == doubleEqualsToken || op.typ == notEqualsToken:
+		p.consume()
+		value := ""
+		switch next := p.peek(); next.typ {
+		case identifierToken:
+			value = p.consume().literal
+		case endOfStringToken, commaToken:
+			// Empty value.
+		default:
+			return nil, fmt.Errorf("found %q, expected value", next.literal)
+		}
+		return NewRequirement(key, Operator(op.literal), []string{value})
+	default:
+		return nil, fmt.Errorf("found %q, expected one of: in, notin, =, ==, !=", op.literal)
+	}
+}
+
+func (p *parser) parseValueSet() ([]string, error) {
+	if t := p.consume(); t.typ != openParToken {
+		return nil, fmt.Errorf("found %q, expected '('", t.literal)
+	}
+	if p.peek().typ == closedParToken {
+		p.consume()
+		return nil, fmt.Errorf("values set can't be empty")
+	}
+	var values []string
+	for {
+		value := ""
+		if p.peek().typ == identifierToken {
+			value = p.consume().literal
+		}
+		values = append(values, value)
+		switch t := p.consume(); t.typ {
+		case commaToken:
+		case closedParToken:
+			return values, nil
+		default:
+			return nil, fmt.Errorf("found %q, expected ',' or ')'", t.literal)
+		}
+	}
+}
+
+// LabelSelectorAsSelector converts the LabelSelector api type into a struct that implements
+// labels.Selector. Nil selector matches nothing, empty selector matches everything.
+func LabelSelectorAsSelector(ps *metav1.LabelSelector) (Selector, error) {
+	if ps == nil {
+		return Nothing(), nil
+	}
+	if len(ps.MatchLabels)+len(ps.MatchExpressions) == 0 {
+		return Everything(), nil
+	}
+	reqs := make([]Requirement, 0, len(ps.MatchLabels)+len(ps.MatchExpressions))
+	for k, v := range ps.MatchLabels {
+		r, err := NewRequirement(k, Equals, []string{v})
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, *r)
+	}
+	for _, expr := range ps.MatchExpressions {
+		var op Operator
+		switch expr.Operator {
+		case metav1.LabelSelectorOpIn:
+			op = In
+		case metav1.LabelSelectorOpNotIn:
+			op = NotIn
+		case metav1.LabelSelectorOpExists:
+			op = Exists
+		case metav1.LabelSelectorOpDoesNotExist:
+			op = DoesNotExist
+		default:
+			return nil, fmt.Errorf("%q is not a valid label selector operator", expr.Operator)
+		}
+		r, err := NewRequirement(expr.Key, op, expr.Values)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, *r)
+	}
+	return NewSelector().Add(reqs...), nil
+}
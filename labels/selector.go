@@ -0,0 +1,284 @@
+package labels
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Selector represents a label selector.
+type Selector interface {
+	// Matches returns true if this selector matches the given set of labels.
+	Matches(Labels) bool
+	// Empty returns true if this selector does not restrict the selection space.
+	Empty() bool
+	// String returns a human readable string that represents this selector.
+	// It is in the format accepted by Parse and API server labelSelector query parameter.
+	String() string
+	// Add adds requirements to the selector and returns new selector.
+	Add(r ...Requirement) Selector
+	// Requirements converts this selector into requirements. Returns false if selector
+	// can't be represented by requirements, e.g. it matches nothing.
+	Requirements() (Requirements, bool)
+}
+
+// Operator is a selector requirement operator.
+type Operator string
+
+const (
+	DoesNotExist Operator = "!"
+	Equals       Operator = "="
+	DoubleEquals Operator = "=="
+	In           Operator = "in"
+	NotEquals    Operator = "!="
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+)
+
+// Requirement contains a key, an operator and a set of values. Requirement is satisfied
+// if labels match operator on values of the key.
+type Requirement struct {
+	key      string
+	operator Operator
+	values   []string
+}
+
+// Requirements is AND of all requirements.
+type Requirements []Requirement
+
+// NewRequirement validates key, operator and values and returns new Requirement.
+// In and NotIn operators require at least one value, Equals, DoubleEquals and NotEquals
+// exactly one value, Exists and DoesNotExist no values.
+func NewRequirement(key string, op Operator, vals []string) (*Requirement, error) {
+	if err := validateLabelKey(key); err != nil {
+		return nil, err
+	}
+	switch op {
+	case In, NotIn:
+		if len(vals) == 0 {
+			return nil, fmt.Errorf("for 'in', 'notin' operators, values set can't be empty")
+		}
+	case Equals, DoubleEquals, NotEquals:
+		if len(vals) != 1 {
+			return nil, fmt.Errorf("exact-match compatibility requires one single value")
+		}
+	case Exists, DoesNotExist:
+		if len(vals) != 0 {
+			return nil, fmt.Errorf("values set must be empty for exists and does not exist")
+		}
+	default:
+		return nil, fmt.Errorf("operator %q is not recognized", op)
+	}
+	for _, v := range vals {
+		if err := validateLabelValue(key, v); err != nil {
+			return nil, err
+		}
+	}
+	values := append([]string(nil), vals...)
+	sort.Strings(values)
+	return &Requirement{key: key, operator: op, values: values}, nil
+}
+
+// Key returns requirement key.
+func (r *Requirement) Key() string {
+	return r.key
+}
+
+// Operator returns requirement operator.
+func (r *Requirement) Operator() Operator {
+	return r.operator
+}
+
+// Values returns sorted requirement values.
+func (r *Requirement) Values() []string {
+	return append([]string(nil), r.values...)
+}
+
+func (r *Requirement) hasValue(value string) bool {
+	for _, v := range r.values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches returns true if the Requirement matches the input Labels.
+func (r *Requirement) Matches(ls Labels) bool {
+	switch r.operator {
+	case In, Equals, DoubleEquals:
+		return ls.Has(r.key) && r.hasValue(ls.Get(r.key))
+	case NotIn, NotEquals:
+		return !ls.Has(r.key) || !r.hasValue(ls.Get(r.key))
+	case Exists:
+		return ls.Has(r.key)
+	case DoesNotExist:
+		return !ls.Has(r.key)
+	default:
+		return false
+	}
+}
+
+// String returns a human-readable string that represents this Requirement.
+func (r *Requirement) String() string {
+	var sb strings.Builder
+	if r.operator == DoesNotExist {
+		sb.WriteString("!")
+	}
+	sb.WriteString(r.key)
+	switch r.operator {
+	case Equals:
+		sb.WriteString("=")
+	case DoubleEquals:
+		sb.WriteString("==")
+	case NotEquals:
+		sb.WriteString("!=")
+	case In:
+		sb.WriteString(" in ")
+	case NotIn:
+		sb.WriteString(" notin ")
+	case Exists, DoesNotExist:
+		return sb.String()
+	}
+	switch r.operator {
+	case In, NotIn:
+		sb.WriteString("(")
+		sb.WriteString(strings.Join(r.values, ","))
+		sb.WriteString(")")
+	default:
+		sb.WriteString(r.values[0])
+	}
+	return sb.String()
+}
+
+// Everything returns a selector that matches all labels.
+func Everything() Selector {
+	return internalSelector{}
+}
+
+// Nothing returns a selector that matches no labels.
+func Nothing() Selector {
+	return nothingSelector{}
+}
+
+// NewSelector returns an empty selector.
+func NewSelector() Selector {
+	return internalSelector{}
+}
+
+// SelectorFromSet returns a Selector which will match exactly the given Set.
+// Set values are not validated.
+func SelectorFromSet(ls Set) Selector {
+	if len(ls) == 0 {
+		return internalSelector{}
+	}
+	selector := make(internalSelector, 0, len(ls))
+	for key, value := range ls {
+		selector = append(selector, Requirement{key: key, operator: Equals, values: []string{value}})
+	}
+	sort.Sort(byKey(selector))
+	return selector
+}
+
+// ValidatedSelectorFromSet returns a Selector which will match exactly the given Set.
+// Error is returned if set contains invalid keys or values.
+func ValidatedSelectorFromSet(ls Set) (Selector, error) {
+	selector := internalSelector{}
+	for key, value := range ls {
+		r, err := NewRequirement(key, Equals, []string{value})
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, *r)
+	}
+	sort.Sort(byKey(selector))
+	return selector, nil
+}
+
+// internalSelector is AND of all requirements.
+type internalSelector []Requirement
+
+type byKey []Requirement
+
+func (a byKey) Len() int           { return len(a) }
+func (a byKey) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byKey) Less(i, j int) bool { return a[i].key < a[j].key }
+
+func (s internalSelector) Matches(ls Labels) bool {
+	for i := range s {
+		if !s[i].Matches(ls) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s internalSelector) Empty() bool {
+	return len(s) == 0
+}
+
+func (s internalSelector) String() string {
+	reqs := make([]string, 0, len(s))
+	for i := range s {
+		reqs = append(reqs, s[i].String())
+	}
+	return strings.Join(reqs, ",")
+}
+
+func (s internalSelector) Add(reqs ...Requirement) Selector {
+	res := make(internalSelector, 0, len(s)+len(reqs))
+	res = append(res, s...)
+	res = append(res, reqs...)
+	sort.Stable(byKey(res))
+	return res
+}
+
+func (s internalSelector) Requirements() (Requirements, bool) {
+	return Requirements(s), true
+}
+
+type nothingSelector struct{}
+
+func (nothingSelector) Matches(_ Labels) bool              { return false }
+func (nothingSelector) Empty() bool                        { return false }
+func (nothingSelector) String() string                     { return "" }
+func (nothingSelector) Add(_ ...Requirement) Selector      { return nothingSelector{} }
+func (nothingSelector) Requirements() (Requirements, bool) { return nil, false }
+
+const (
+	qualifiedNameMaxLength = 63
+	dns1123SubdomainMaxLen = 253
+	labelValueMaxLength    = 63
+)
+
+var (
+	qualifiedNameRegexp   = regexp.MustCompile("^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$")
+	dns1123SubdomainRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	labelValueRegexp      = regexp.MustCompile("^(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?$")
+)
+
+// validateLabelKey checks that key is a qualified name with optional DNS subdomain prefix.
+func validateLabelKey(key string) error {
+	name := key
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if len(prefix) == 0 || len(prefix) > dns1123SubdomainMaxLen || !dns1123SubdomainRegex.MatchString(prefix) {
+			return fmt.Errorf("invalid label key %q: prefix part must be a valid DNS subdomain", key)
+		}
+	}
+	if len(name) == 0 || len(name) > qualifiedNameMaxLength || !qualifiedNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid label key %q: name part must consist of alphanumeric characters, '-', '_' or '.', "+
+			"must start and end with an alphanumeric character and be no more than %d characters", key, qualifiedNameMaxLength)
+	}
+	return nil
+}
+
+func validateLabelValue(key, value string) error {
+	if len(value) > labelValueMaxLength || !labelValueRegexp.MatchString(value) {
+		return fmt.Errorf("invalid label value %q for key %q: must be %d characters or less, consist of alphanumeric characters, "+
+			"'-', '_' or '.' and start and end with an alphanumeric character", value, key, labelValueMaxLength)
+	}
+	return nil
+}
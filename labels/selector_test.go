@@ -0,0 +1,89 @@
+package labels
+
+import (
+	"testing"
+
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		selector string
+		labels   Set
+		matches  bool
+		str      string
+	}{
+		{selector: "", labels: Set{"app": "a"}, matches: true, str: ""},
+		{selector: "app=a", labels: Set{"app": "a"}, matches: true, str: "app=a"},
+		{selector: "app==a", labels: Set{"app": "b"}, matches: false, str: "app==a"},
+		{selector: "app!=a", labels: Set{"app": "b"}, matches: true, str: "app!=a"},
+		{selector: "app!=a", labels: Set{}, matches: true, str: "app!=a"},
+		{selector: "env in (prod, staging)", labels: Set{"env": "staging"}, matches: true, str: "env in (prod,staging)"},
+		{selector: "env notin (prod,staging)", labels: Set{"env": "prod"}, matches: false, str: "env notin (prod,staging)"},
+		{selector: "app", labels: Set{"app": ""}, matches: true, str: "app"},
+		{selector: "!app", labels: Set{"app": "a"}, matches: false, str: "!app"},
+		{selector: "tier=web,app.kubernetes.io/name=api", labels: Set{"app.kubernetes.io/name": "api", "tier": "web"}, matches: true, str: "app.kubernetes.io/name=api,tier=web"},
+		{selector: "app=", labels: Set{"app": ""}, matches: true, str: "app="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			s, err := Parse(tt.selector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.Matches(tt.labels) != tt.matches {
+				t.Fatalf("expected match %v for labels %v", tt.matches, tt.labels)
+			}
+			if s.String() != tt.str {
+				t.Fatalf("expected string %q, got %q", tt.str, s.String())
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, selector := range []string{
+		"app=a,",
+		"app in ()",
+		"app in (a",
+		"app=a=b",
+		"!",
+		"x y",
+		"-app=a",
+		"app=-a",
+	} {
+		if _, err := Parse(selector); err == nil {
+			t.Fatalf("expected error for selector %q", selector)
+		}
+	}
+}
+
+func TestLabelSelectorAsSelector(t *testing.T) {
+	s, err := LabelSelectorAsSelector(&metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": "api"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"prod"}},
+			{Key: "canary", Operator: metav1.LabelSelectorOpDoesNotExist},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "app=api,!canary,env in (prod)"; s.String() != expected {
+		t.Fatalf("expected string %q, got %q", expected, s.String())
+	}
+	if !s.Matches(Set{"app": "api", "env": "prod"}) {
+		t.Fatal("expected selector to match")
+	}
+	if s.Matches(Set{"app": "api", "env": "prod", "canary": "true"}) {
+		t.Fatal("expected selector not to match")
+	}
+
+	nothing, err := LabelSelectorAsSelector(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nothing.Matches(Set{}) {
+		t.Fatal("expected nil selector to match nothing")
+	}
+}
@@ -0,0 +1,337 @@
+package leaderelection
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	client "github.com/castai/k8s-client-go"
+	coordinationv1 "github.com/castai/k8s-client-go/types/coordination/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+// JitterFactor is used to spread acquire attempts of candidates in time.
+const JitterFactor = 1.2
+
+// LeaseClient is subset of ObjectAPI needed to manage the lease.
+type LeaseClient interface {
+	client.ObjectGetter[coordinationv1.Lease]
+	client.ObjectCreator[coordinationv1.Lease]
+	client.ObjectUpdater[coordinationv1.Lease]
+}
+
+// LeaderCallbacks are callbacks that are triggered during certain lifecycle events of the LeaderElector.
+type LeaderCallbacks struct {
+	// OnStartedLeading is called in a new goroutine when leadership is acquired. Context is canceled
+	// when leadership is lost or elector is stopped.
+	OnStartedLeading func(ctx context.Context)
+	// OnStoppedLeading is called when elector stops leading.
+	OnStoppedLeading func()
+	// OnNewLeader is called in a new goroutine when observed leader changes. Optional.
+	OnNewLeader func(identity string)
+}
+
+// Config is LeaderElector configuration.
+type Config struct {
+	// LeaseNamespace and LeaseName identify the coordination.k8s.io/v1 Lease used as a lock.
+	LeaseNamespace string
+	LeaseName      string
+	// Identity is unique identity of this candidate, e.g. pod name.
+	Identity string
+
+	// LeaseDuration is the duration that non-leader candidates will wait to force acquire leadership.
+	// It is measured against time of last observed lease change.
+	LeaseDuration time.Duration
+	// RenewDeadline is the duration that the acting leader will retry refreshing leadership before giving up.
+	RenewDeadline time.Duration
+	// RetryPeriod is the duration candidates should wait between tries of actions.
+	RetryPeriod time.Duration
+
+	Callbacks LeaderCallbacks
+
+	// ReleaseOnCancel releases the lease when context passed to Run is canceled, so other candidates can
+	// acquire it without waiting for LeaseDuration. Leader must ensure all work is stopped before
+	// OnStartedLeading context is canceled.
+	ReleaseOnCancel bool
+
+	// Log is used to report lease errors. Defaults to client.DefaultLogger.
+	Log client.Logger
+}
+
+// LeaderElector is a leader election client.
+type LeaderElector struct {
+	leases LeaseClient
+	cfg    Config
+
+	mu             sync.Mutex
+	observedLease  *coordinationv1.Lease
+	observedTime   time.Time
+	reportedLeader string
+}
+
+// NewLeaderElector validates config and creates LeaderElector.
+func NewLeaderElector(leases LeaseClient, cfg Config) (*LeaderElector, error) {
+	if cfg.LeaseDuration <= cfg.RenewDeadline {
+		return nil, fmt.Errorf("leaseDuration must be greater than renewDeadline")
+	}
+	if cfg.RenewDeadline <= time.Duration(JitterFactor*float64(cfg.RetryPeriod)) {
+		return nil, fmt.Errorf("renewDeadline must be greater than retryPeriod*JitterFactor")
+	}
+	if cfg.RetryPeriod < 1 {
+		return nil, fmt.Errorf("retryPeriod must be greater than zero")
+	}
+	if cfg.LeaseName == "" {
+		return nil, fmt.Errorf("lease name must not be empty")
+	}
+	if cfg.Identity == "" {
+		return nil, fmt.Errorf("leader election identity must not be empty")
+	}
+	if cfg.Callbacks.OnStartedLeading == nil {
+		return nil, fmt.Errorf("OnStartedLeading callback must not be nil")
+	}
+	if cfg.Callbacks.OnStoppedLeading == nil {
+		return nil, fmt.Errorf("OnStoppedLeading callback must not be nil")
+	}
+	if cfg.Log == nil {
+		cfg.Log = &client.DefaultLogger{}
+	}
+	return &LeaderElector{
+		leases: leases,
+		cfg:    cfg,
+	}, nil
+}
+
+// Run blocks until leadership is acquired, runs OnStartedLeading and keeps renewing the lease until
+// context is done or leadership is lost. OnStoppedLeading is called only if leadership was acquired.
+func (le *LeaderElector) Run(ctx context.Context) {
+	if !le.acquire(ctx) {
+		return
+	}
+	defer le.cfg.Callbacks.OnStoppedLeading()
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go le.cfg.Callbacks.OnStartedLeading(leaderCtx)
+	le.renew(leaderCtx)
+}
+
+// IsLeader returns true if the last observed lease holder is this candidate.
+func (le *LeaderElector) IsLeader() bool {
+	return le.GetLeader() == le.cfg.Identity
+}
+
+// GetLeader returns identity of the last observed lease holder.
+func (le *LeaderElector) GetLeader() string {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+	if le.observedLease == nil || le.observedLease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *le.observedLease.Spec.HolderIdentity
+}
+
+// acquire loops until lease is acquired or context is done.
+func (le *LeaderElector) acquire(ctx context.Context) bool {
+	for {
+		if le.tryAcquireOrRenew(ctx) {
+			le.cfg.Log.Infof("k8s-client-go: successfully acquired lease %s/%s", le.cfg.LeaseNamespace, le.cfg.LeaseName)
+			return true
+		}
+		if !sleep(ctx, jitter(le.cfg.RetryPeriod, JitterFactor)) {
+			return false
+		}
+	}
+}
+
+// renew loops until renewal fails within RenewDeadline or context is done.
+func (le *LeaderElector) renew(ctx context.Context) {
+	for {
+		deadlineCtx, cancel := context.WithTimeout(ctx, le.cfg.RenewDeadline)
+		renewed := le.renewWithinDeadline(deadlineCtx)
+		cancel()
+		if !renewed {
+			break
+		}
+		if !sleep(ctx, le.cfg.RetryPeriod) {
+			break
+		}
+	}
+
+	if ctx.Err() == nil {
+		le.cfg.Log.Infof("k8s-client-go: failed to renew lease %s/%s", le.cfg.LeaseNamespace, le.cfg.LeaseName)
+		return
+	}
+	if le.cfg.ReleaseOnCancel {
+		le.release()
+	}
+}
+
+func (le *LeaderElector) renewWithinDeadline(ctx context.Context) bool {
+	for {
+		if le.tryAcquireOrRenew(ctx) {
+			return true
+		}
+		if !sleep(ctx, le.cfg.RetryPeriod) {
+			return false
+		}
+	}
+}
+
+// tryAcquireOrRenew creates the lease or updates it if it's held by this candidate or expired.
+// Returns true on success.
+func (le *LeaderElector) tryAcquireOrRenew(ctx context.Context) bool {
+	now := metav1.NowMicro()
+	spec := coordinationv1.LeaseSpec{
+		HolderIdentity:       stringPtr(le.cfg.Identity),
+		LeaseDurationSeconds: int32Ptr(int32(le.cfg.LeaseDuration / time.Second)),
+		AcquireTime:          &now,
+		RenewTime:            &now,
+		LeaseTransitions:     int32Ptr(0),
+	}
+
+	old, err := le.leases.Get(ctx, le.cfg.LeaseNamespace, le.cfg.LeaseName, metav1.GetOptions{})
+	if err != nil {
+		if !client.IsNotFound(err) {
+			le.cfg.Log.Infof("k8s-client-go: error retrieving lease %s/%s: %v", le.cfg.LeaseNamespace, le.cfg.LeaseName, err)
+			return false
+		}
+		lease := &coordinationv1.Lease{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "coordination.k8s.io/v1",
+				Kind:       "Lease",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: le.cfg.LeaseNamespace,
+				Name:      le.cfg.LeaseName,
+			},
+			Spec: spec,
+		}
+		created, err := le.leases.Create(ctx, le.cfg.LeaseNamespace, lease, metav1.CreateOptions{})
+		if err != nil {
+			le.cfg.Log.Infof("k8s-client-go: error creating lease %s/%s: %v", le.cfg.LeaseNamespace, le.cfg.LeaseName, err)
+			return false
+		}
+		le.setObservedLease(created)
+		return true
+	}
+
+	le.observe(old)
+	holder := ""
+	if old.Spec.HolderIdentity != nil {
+		holder = *old.Spec.HolderIdentity
+	}
+	if holder != "" && holder != le.cfg.Identity && !le.observedLeaseExpired() {
+		return false
+	}
+
+	// Keep acquire time and transitions if lease is already held by this candidate.
+	if holder == le.cfg.Identity {
+		spec.AcquireTime = old.Spec.AcquireTime
+		spec.LeaseTransitions = old.Spec.LeaseTransitions
+	} else if old.Spec.LeaseTransitions != nil {
+		spec.LeaseTransitions = int32Ptr(*old.Spec.LeaseTransitions + 1)
+	}
+
+	lease := *old
+	lease.Spec = spec
+	updated, err := le.leases.Update(ctx, le.cfg.LeaseNamespace, &lease, metav1.UpdateOptions{})
+	if err != nil {
+		le.cfg.Log.Infof("k8s-client-go: failed to update lease %s/%s: %v", le.cfg.LeaseNamespace, le.cfg.LeaseName, err)
+		return false
+	}
+	le.setObservedLease(updated)
+	return true
+}
+
+// release clears lease holder if this candidate is the leader.
+func (le *LeaderElector) release() {
+	if !le.IsLeader() {
+		return
+	}
+	le.mu.Lock()
+	lease := *le.observedLease
+	le.mu.Unlock()
+
+	now := metav1.NowMicro()
+	lease.Spec = coordinationv1.LeaseSpec{
+		LeaseDurationSeconds: int32Ptr(1),
+		AcquireTime:          &now,
+		RenewTime:            &now,
+		LeaseTransitions:     lease.Spec.LeaseTransitions,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), le.cfg.RenewDeadline)
+	defer cancel()
+	updated, err := le.leases.Update(ctx, le.cfg.LeaseNamespace, &lease, metav1.UpdateOptions{})
+	if err != nil {
+		le.cfg.Log.Infof("k8s-client-go: failed to release lease %s/%s: %v", le.cfg.LeaseNamespace, le.cfg.LeaseName, err)
+		return
+	}
+	le.setObservedLease(updated)
+}
+
+// observe records the lease and time when it was seen changed. Lease expiration is measured against
+// local observation time to be independent of clock skew between candidates.
+func (le *LeaderElector) observe(lease *coordinationv1.Lease) {
+	le.mu.Lock()
+	changed := le.observedLease == nil ||
+		le.observedLease.ResourceVersion != lease.ResourceVersion
+	le.mu.Unlock()
+	if changed {
+		le.setObservedLease(lease)
+	}
+}
+
+func (le *LeaderElector) setObservedLease(lease *coordinationv1.Lease) {
+	le.mu.Lock()
+	le.observedLease = lease
+	le.observedTime = time.Now()
+	le.mu.Unlock()
+	le.maybeReportTransition()
+}
+
+func (le *LeaderElector) observedLeaseExpired() bool {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+	return le.observedTime.Add(le.cfg.LeaseDuration).Before(time.Now())
+}
+
+func (le *LeaderElector) maybeReportTransition() {
+	leader := le.GetLeader()
+	le.mu.Lock()
+	if leader == le.reportedLeader {
+		le.mu.Unlock()
+		return
+	}
+	le.reportedLeader = leader
+	le.mu.Unlock()
+	if le.cfg.Callbacks.OnNewLeader != nil {
+		go le.cfg.Callbacks.OnNewLeader(leader)
+	}
+}
+
+// sleep waits for given duration. Returns false if context is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// jitter returns duration between d and d+maxFactor*d.
+func jitter(d time.Duration, maxFactor float64) time.Duration {
+	return d + time.Duration(rand.Float64()*maxFactor*float64(d))
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
@@ -0,0 +1,164 @@
+package leaderelection
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	client "github.com/castai/k8s-client-go"
+	coordinationv1 "github.com/castai/k8s-client-go/types/coordination/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+func TestLeaderElection(t *testing.T) {
+	leases := &fakeLeases{}
+
+	started := make(chan string, 2)
+	stopped := make(chan string, 2)
+	newElector := func(identity string) *LeaderElector {
+		le, err := NewLeaderElector(leases, Config{
+			LeaseNamespace:  "test",
+			LeaseName:       "lock",
+			Identity:        identity,
+			LeaseDuration:   time.Second,
+			RenewDeadline:   500 * time.Millisecond,
+			RetryPeriod:     10 * time.Millisecond,
+			ReleaseOnCancel: true,
+			Callbacks: LeaderCallbacks{
+				OnStartedLeading: func(ctx context.Context) { started <- identity },
+				OnStoppedLeading: func() { stopped <- identity },
+			},
+			Log: &testLogger{t: t},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return le
+	}
+
+	le1 := newElector("candidate1")
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	done1 := make(chan struct{})
+	go func() {
+		le1.Run(ctx1)
+		close(done1)
+	}()
+	if id := receive(t, started); id != "candidate1" {
+		t.Fatalf("expected candidate1 to lead, got %q", id)
+	}
+
+	le2 := newElector("candidate2")
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	go le2.Run(ctx2)
+
+	// Second candidate must wait while lease is renewed.
+	select {
+	case id := <-started:
+		t.Fatalf("expected no new leader, got %q", id)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if le2.GetLeader() != "candidate1" || le2.IsLeader() {
+		t.Fatalf("expected candidate2 to observe candidate1 as leader, got %q", le2.GetLeader())
+	}
+
+	// Released lease is acquired without waiting for lease duration.
+	cancel1()
+	<-done1
+	if id := receive(t, stopped); id != "candidate1" {
+		t.Fatalf("expected candidate1 to stop leading, got %q", id)
+	}
+	select {
+	case id := <-started:
+		if id != "candidate2" {
+			t.Fatalf("expected candidate2 to lead, got %q", id)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("timeout waiting for candidate2 to acquire released lease")
+	}
+
+	lease, _ := leases.Get(context.Background(), "test", "lock", metav1.GetOptions{})
+	if *lease.Spec.LeaseTransitions != 1 {
+		t.Fatalf("expected 1 lease transition, got %d", *lease.Spec.LeaseTransitions)
+	}
+}
+
+func TestNewLeaderElectorValidation(t *testing.T) {
+	_, err := NewLeaderElector(&fakeLeases{}, Config{
+		LeaseName:     "lock",
+		Identity:      "candidate",
+		LeaseDuration: time.Second,
+		RenewDeadline: 2 * time.Second,
+		RetryPeriod:   100 * time.Millisecond,
+	})
+	if err == nil {
+		t.Fatal("expected error when renew deadline exceeds lease duration")
+	}
+}
+
+func receive(t *testing.T, ch chan string) string {
+	t.Helper()
+	select {
+	case id := <-ch:
+		return id
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for callback")
+		return ""
+	}
+}
+
+// fakeLeases stores single lease in memory and rejects updates with stale resource version.
+type fakeLeases struct {
+	mu    sync.Mutex
+	lease *coordinationv1.Lease
+	rv    int
+}
+
+func (f *fakeLeases) Get(_ context.Context, _, name string, _ metav1.GetOptions) (*coordinationv1.Lease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.lease == nil {
+		return nil, client.NewNotFound(coordinationv1.Lease{}.GVR(), name)
+	}
+	lease := *f.lease
+	return &lease, nil
+}
+
+func (f *fakeLeases) Create(_ context.Context, _ string, obj *coordinationv1.Lease, _ metav1.CreateOptions) (*coordinationv1.Lease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.lease != nil {
+		return nil, &client.StatusError{ErrStatus: metav1.Status{Code: http.StatusConflict, Reason: metav1.StatusReasonAlreadyExists}}
+	}
+	return f.store(obj), nil
+}
+
+func (f *fakeLeases) Update(_ context.Context, _ string, obj *coordinationv1.Lease, _ metav1.UpdateOptions) (*coordinationv1.Lease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.lease == nil || f.lease.ResourceVersion != obj.ResourceVersion {
+		return nil, &client.StatusError{ErrStatus: metav1.Status{Code: http.StatusConflict, Reason: metav1.StatusReasonConflict}}
+	}
+	return f.store(obj), nil
+}
+
+func (f *fakeLeases) store(obj *coordinationv1.Lease) *coordinationv1.Lease {
+	f.rv++
+	lease := *obj
+	lease.ResourceVersion = strconv.Itoa(f.rv)
+	f.lease = &lease
+	res := lease
+	return &res
+}
+
+type testLogger struct {
+	t *testing.T
+}
+
+func (l *testLogger) Infof(format string, args ...any) {
+	l.t.Logf(format, args...)
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+// ListPages lists objects page by page following continue tokens until all pages are received
+// or fn returns an error. Page size is controlled by opts.Limit.
+func ListPages[T corev1.Object](ctx context.Context, lister ObjectLister[T], namespace string, opts metav1.ListOptions, fn func(page *corev1.List[T]) error) error {
+	for {
+		page, err := lister.List(ctx, namespace, opts)
+		if err != nil {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if page.Continue == "" {
+			return nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// ListAll lists all objects by walking all pages and returns them as a single list.
+// Returned list metadata contains resource version of the first page, which is the
+// same for all pages of a consistent list.
+func ListAll[T corev1.Object](ctx context.Context, lister ObjectLister[T], namespace string, opts metav1.ListOptions) (*corev1.List[T], error) {
+	var res *corev1.List[T]
+	err := ListPages(ctx, lister, namespace, opts, func(page *corev1.List[T]) error {
+		if res == nil {
+			res = page
+			return nil
+		}
+		res.Items = append(res.Items, page.Items...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Continue = ""
+	res.RemainingItemCount = nil
+	return res, nil
+}
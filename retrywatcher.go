@@ -0,0 +1,226 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+type RetryWatcherOption func(opts *retryWatcherOptions)
+type retryWatcherOptions struct {
+	log            Logger
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// WithRetryLogger sets logger used to report watch failures.
+func WithRetryLogger(log Logger) RetryWatcherOption {
+	return func(opts *retryWatcherOptions) {
+		opts.log = log
+	}
+}
+
+// WithRetryBackoff sets initial and max delay between watch retries. Delay is doubled after each
+// failed attempt and reset once watch delivers events again.
+func WithRetryBackoff(initial, maxDelay time.Duration) RetryWatcherOption {
+	return func(opts *retryWatcherOptions) {
+		opts.initialBackoff = initial
+		opts.maxBackoff = maxDelay
+	}
+}
+
+// RetryWatcher is a watcher which re-establishes underlying watch from the last observed resource version
+// when connection to API server is closed or fails. It stops only when context is done, Stop is called or
+// resource version becomes too old (410 Gone), in which case Err returns *StatusError and caller should relist.
+type RetryWatcher[T corev1.Object] struct {
+	watcher   ObjectWatcher[T]
+	namespace string
+	name      string
+	opts      metav1.ListOptions
+	ropts     retryWatcherOptions
+
+	result   chan corev1.Event[T]
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	stopOnce sync.Once
+
+	mu              sync.Mutex
+	resourceVersion string
+	err             error
+}
+
+var _ WatchInterface[corev1.Endpoints] = (*RetryWatcher[corev1.Endpoints])(nil)
+
+// NewRetryWatcher creates and starts RetryWatcher. Initial resource version must be set in opts.ResourceVersion,
+// usually it is taken from the list response. Watch is established with bookmarks allowed to keep resource
+// version fresh.
+func NewRetryWatcher[T corev1.Object](ctx context.Context, watcher ObjectWatcher[T], namespace, name string, opts metav1.ListOptions, opt ...RetryWatcherOption) (*RetryWatcher[T], error) {
+	switch opts.ResourceVersion {
+	case "", "0":
+		return nil, fmt.Errorf("initial resource version %q is not supported by retry watcher", opts.ResourceVersion)
+	}
+	ropts := retryWatcherOptions{
+		log:            &DefaultLogger{},
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     30 * time.Second,
+	}
+	for _, o := range opt {
+		o(&ropts)
+	}
+	opts.AllowWatchBookmarks = true
+
+	rw := &RetryWatcher[T]{
+		watcher:         watcher,
+		namespace:       namespace,
+		name:            name,
+		opts:            opts,
+		ropts:           ropts,
+		result:          make(chan corev1.Event[T]),
+		stopCh:          make(chan struct{}),
+		doneCh:          make(chan struct{}),
+		resourceVersion: opts.ResourceVersion,
+	}
+	go rw.receive(ctx)
+	return rw, nil
+}
+
+// ResultChan implements WatchInterface.
+func (rw *RetryWatcher[T]) ResultChan() <-chan corev1.Event[T] {
+	return rw.result
+}
+
+// Stop implements WatchInterface.
+func (rw *RetryWatcher[T]) Stop() {
+	rw.stopOnce.Do(func() {
+		close(rw.stopCh)
+	})
+}
+
+// ResourceVersion implements WatchInterface.
+func (rw *RetryWatcher[T]) ResourceVersion() string {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	return rw.resourceVersion
+}
+
+// Done is closed when watcher is stopped and result channel is closed.
+func (rw *RetryWatcher[T]) Done() <-chan struct{} {
+	return rw.doneCh
+}
+
+// Err returns error which terminated the watcher. It is *StatusError with 410 Gone code if resource
+// version expired, nil if watcher was stopped.
+func (rw *RetryWatcher[T]) Err() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	return rw.err
+}
+
+func (rw *RetryWatcher[T]) setResourceVersion(rv string) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	rw.resourceVersion = rv
+}
+
+func (rw *RetryWatcher[T]) receive(ctx context.Context) {
+	defer close(rw.doneCh)
+	defer close(rw.result)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-rw.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	backoff := rw.ropts.initialBackoff
+	for {
+		progressed, status := rw.doReceive(ctx)
+		if status != nil {
+			rw.mu.Lock()
+			rw.err = &StatusError{ErrStatus: *status}
+			rw.mu.Unlock()
+			select {
+			case rw.result <- corev1.Event[T]{Type: corev1.EventTypeError, Status: status}:
+			case <-ctx.Done():
+			}
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if progressed {
+			backoff = rw.ropts.initialBackoff
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > rw.ropts.maxBackoff {
+			backoff = rw.ropts.maxBackoff
+		}
+	}
+}
+
+// doReceive runs single watch until it ends. It returns whether any event was received and
+// non-nil status if watch can't be resumed because resource version is gone.
+func (rw *RetryWatcher[T]) doReceive(ctx context.Context) (bool, *metav1.Status) {
+	opts := rw.opts
+	opts.ResourceVersion = rw.ResourceVersion()
+	w, err := rw.watcher.Watch(ctx, rw.namespace, rw.name, opts)
+	if err != nil {
+		if IsGone(err) {
+			status, _ := statusForError(err)
+			return false, &status
+		}
+		if ctx.Err() == nil {
+			rw.ropts.log.Infof("k8s-client-go: failed to watch from resource version %q: %v", opts.ResourceVersion, err)
+		}
+		return false, nil
+	}
+	defer w.Stop()
+
+	var progressed bool
+	for {
+		select {
+		case <-ctx.Done():
+			return progressed, nil
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				// Underlying watcher also tracks filtered out bookmarks.
+				if rv := w.ResourceVersion(); rv != "" {
+					rw.setResourceVersion(rv)
+				}
+				return progressed, nil
+			}
+			if e.Type == corev1.EventTypeError {
+				if e.Status != nil {
+					statusErr := &StatusError{ErrStatus: *e.Status}
+					if IsGone(statusErr) {
+						return progressed, e.Status
+					}
+				}
+				rw.ropts.log.Infof("k8s-client-go: watch error event received, retrying: %+v", e.Status)
+				return progressed, nil
+			}
+			progressed = true
+			rw.setResourceVersion((*e.Object).GetObjectMeta().ResourceVersion)
+			select {
+			case rw.result <- e:
+			case <-ctx.Done():
+				return progressed, nil
+			}
+		}
+	}
+}
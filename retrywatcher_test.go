@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+func TestRetryWatcher(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rv := r.URL.Query().Get("resourceVersion")
+		var body string
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			if rv != "1" {
+				t.Errorf("expected initial resource version %q, got %q", "1", rv)
+			}
+			body = `{"type":"ADDED","object":{"metadata":{"name":"endpoint1","resourceVersion":"2"}}}`
+		case 2:
+			if rv != "2" {
+				t.Errorf("expected resumed resource version %q, got %q", "2", rv)
+			}
+			body = `{"type":"BOOKMARK","object":{"metadata":{"resourceVersion":"3"}}}`
+		default:
+			if rv != "3" {
+				t.Errorf("expected resumed resource version %q, got %q", "3", rv)
+			}
+			body = `{"type":"ERROR","object":{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Expired","code":410}}`
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+	client := &mockClient{
+		apiServerURL: srv.URL,
+		hc:           &http.Client{Timeout: 5 * time.Second},
+	}
+
+	api := NewObjectAPI[corev1.Endpoints](client, WithSkipBookmarks())
+
+	rw, err := NewRetryWatcher[corev1.Endpoints](context.Background(), api, "test", "", metav1.ListOptions{ResourceVersion: "1"}, WithRetryBackoff(time.Millisecond, 10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rw.Stop()
+
+	var events []corev1.EventType
+	for e := range rw.ResultChan() {
+		events = append(events, e.Type)
+	}
+	if len(events) != 2 || events[0] != corev1.EventTypeAdded || events[1] != corev1.EventTypeError {
+		t.Fatalf("unexpected events %v", events)
+	}
+	if !IsGone(rw.Err()) {
+		t.Fatalf("expected gone error, got %v", rw.Err())
+	}
+	if rv := rw.ResourceVersion(); rv != "3" {
+		t.Fatalf("expected resource version %q, got %q", "3", rv)
+	}
+}
+
+func TestRetryWatcherStop(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	client := &mockClient{
+		apiServerURL: srv.URL,
+		hc:           &http.Client{Timeout: 5 * time.Second},
+	}
+
+	api := NewObjectAPI[corev1.Endpoints](client)
+
+	rw, err := NewRetryWatcher[corev1.Endpoints](context.Background(), api, "test", "", metav1.ListOptions{ResourceVersion: "1"}, WithRetryBackoff(time.Millisecond, 10*time.Millisecond), WithRetryLogger(&testLogger{t: t}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	rw.Stop()
+
+	select {
+	case <-rw.Done():
+	case <-time.After(time.Second):
+		t.Fatal("retry watcher was not stopped")
+	}
+	if rw.Err() != nil {
+		t.Fatalf("expected no error after stop, got %v", rw.Err())
+	}
+}
+
+type testLogger struct {
+	t *testing.T
+}
+
+func (l *testLogger) Infof(format string, args ...any) {
+	l.t.Logf(format, args...)
+}
@@ -1,11 +1,14 @@
 package client
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"sync"
 
 	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
 )
 
 // ResponseDecoder allows to specify custom JSON response decoder. By default, std json decoder is used.
@@ -23,26 +26,44 @@ type WatchInterface[T corev1.Object] interface {
 	// or Stop() is called, this channel will be closed, in which case the
 	// Watch should be completely cleaned up.
 	ResultChan() <-chan corev1.Event[T]
+
+	// ResourceVersion returns the latest resource version observed in the watch stream,
+	// including bookmarks. It can be used to resume watch after disconnect.
+	ResourceVersion() string
 }
 
 // StreamWatcher turns any stream for which you can write a Decoder interface
 // into a Watch.Interface.
 type streamWatcher[T corev1.Object] struct {
-	result  chan corev1.Event[T]
-	r       io.ReadCloser
-	log     Logger
-	decoder ResponseDecoder
+	result     chan corev1.Event[T]
+	r          io.ReadCloser
+	log        Logger
+	decodeFunc ResponseDecoderFunc
+	decoder    ResponseDecoder
+	// skipBookmarks disables sending of bookmark events to result channel.
+	skipBookmarks bool
 	sync.Mutex
-	stopped bool
+	stopped         bool
+	done            chan struct{}
+	resourceVersion string
+}
+
+// rawEvent is a watch event with not yet decoded object, which can be either T or Status.
+type rawEvent struct {
+	Type   corev1.EventType `json:"type"`
+	Object json.RawMessage  `json:"object"`
 }
 
 // NewStreamWatcher creates a StreamWatcher from the given io.ReadClosers.
-func newStreamWatcher[T corev1.Object](r io.ReadCloser, log Logger, decoder ResponseDecoder) WatchInterface[T] {
+func newStreamWatcher[T corev1.Object](r io.ReadCloser, log Logger, decodeFunc ResponseDecoderFunc, skipBookmarks bool) WatchInterface[T] {
 	sw := &streamWatcher[T]{
-		r:       r,
-		log:     log,
-		decoder: decoder,
-		result:  make(chan corev1.Event[T]),
+		r:             r,
+		log:           log,
+		decodeFunc:    decodeFunc,
+		decoder:       decodeFunc(r),
+		skipBookmarks: skipBookmarks,
+		result:        make(chan corev1.Event[T]),
+		done:          make(chan struct{}),
 	}
 	go sw.receive()
 	return sw
@@ -53,12 +74,20 @@ func (sw *streamWatcher[T]) ResultChan() <-chan corev1.Event[T] {
 	return sw.result
 }
 
+// ResourceVersion implements Interface.
+func (sw *streamWatcher[T]) ResourceVersion() string {
+	sw.Lock()
+	defer sw.Unlock()
+	return sw.resourceVersion
+}
+
 // Stop implements Interface.
 func (sw *streamWatcher[T]) Stop() {
 	sw.Lock()
 	defer sw.Unlock()
 	if !sw.stopped {
 		sw.stopped = true
+		close(sw.done)
 		sw.r.Close()
 	}
 }
@@ -91,19 +120,45 @@ func (sw *streamWatcher[T]) receive() {
 			}
 			return
 		}
-		sw.result <- obj
+		if obj.Object != nil {
+			sw.Lock()
+			sw.resourceVersion = (*obj.Object).GetObjectMeta().ResourceVersion
+			sw.Unlock()
+		}
+		if obj.Type == corev1.EventTypeBookmark && sw.skipBookmarks {
+			continue
+		}
+		select {
+		case sw.result <- obj:
+		case <-sw.done:
+			// Consumer may not read results after Stop.
+			return
+		}
 	}
 }
 
 // Decode blocks until it can return the next object in the writer. Returns an error
 // if the writer is closed or an object can't be decoded.
 func (sw *streamWatcher[T]) Decode() (corev1.Event[T], error) {
-	var t corev1.Event[T]
-	if err := sw.decoder.Decode(&t); err != nil {
-		return t, err
+	var raw rawEvent
+	if err := sw.decoder.Decode(&raw); err != nil {
+		return corev1.Event[T]{}, err
 	}
-	switch t.Type {
-	case corev1.EventTypeAdded, corev1.EventTypeModified, corev1.EventTypeDeleted, corev1.EventTypeError:
+	t := corev1.Event[T]{Type: raw.Type}
+	switch raw.Type {
+	case corev1.EventTypeAdded, corev1.EventTypeModified, corev1.EventTypeDeleted, corev1.EventTypeBookmark:
+		var obj T
+		if err := sw.decodeFunc(bytes.NewReader(raw.Object)).Decode(&obj); err != nil {
+			return t, fmt.Errorf("unable to decode watch event object: %w", err)
+		}
+		t.Object = &obj
+		return t, nil
+	case corev1.EventTypeError:
+		var status metav1.Status
+		if err := sw.decodeFunc(bytes.NewReader(raw.Object)).Decode(&status); err != nil {
+			return t, fmt.Errorf("unable to decode watch error status: %w", err)
+		}
+		t.Status = &status
 		return t, nil
 	default:
 		return t, fmt.Errorf("got invalid Watch event type: %v", t.Type)
@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// TokenSource provides access token for API requests. If TokenSource implements io.Closer,
+// it's closed by DefaultClient.Close.
+type TokenSource interface {
+	// Token returns current access token.
+	Token() (string, error)
+	// Invalidate is called when API server rejects the token with 401. Next Token call should
+	// return fresh token if possible.
+	Invalidate(token string)
+}
+
+// StaticTokenSource returns TokenSource which always returns the same token.
+func StaticTokenSource(token string) TokenSource {
+	return staticTokenSource(token)
+}
+
+type staticTokenSource string
+
+func (s staticTokenSource) Token() (string, error) {
+	return string(s), nil
+}
+
+func (s staticTokenSource) Invalidate(_ string) {}
+
+type FileTokenSourceOption func(opts *fileTokenSourceOptions)
+type fileTokenSourceOptions struct {
+	refreshInterval time.Duration
+}
+
+// WithTokenRefreshInterval sets how often token file is re-read regardless of file change notifications.
+// Default is 1 minute.
+func WithTokenRefreshInterval(interval time.Duration) FileTokenSourceOption {
+	return func(opts *fileTokenSourceOptions) {
+		opts.refreshInterval = interval
+	}
+}
+
+// FileTokenSource reads token from a file and keeps it up to date. Directory of the file is watched, so
+// both in-place writes and atomic symlink swaps, used by kubelet to rotate projected service account
+// tokens, are noticed. File is also re-read periodically in case notification is missed or file watching
+// is not available, and when API server rejects the token.
+type FileTokenSource struct {
+	path string
+	opts fileTokenSourceOptions
+
+	mu    sync.RWMutex
+	token string
+
+	watcher   *fsnotify.Watcher
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+	closeOnce sync.Once
+}
+
+var _ TokenSource = (*FileTokenSource)(nil)
+
+// NewFileTokenSource reads token from the file and starts watching it. Close must be called to stop watching.
+func NewFileTokenSource(path string, opt ...FileTokenSourceOption) (*FileTokenSource, error) {
+	opts := fileTokenSourceOptions{
+		refreshInterval: time.Minute,
+	}
+	for _, o := range opt {
+		o(&opts)
+	}
+	s := &FileTokenSource{
+		path:   path,
+		opts:   opts,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	// Watching is best effort, periodic re-read still keeps token fresh if it's not available.
+	if watcher, err := fsnotify.NewWatcher(); err == nil {
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			watcher.Close()
+		} else {
+			s.watcher = watcher
+		}
+	}
+
+	go s.run()
+	return s, nil
+}
+
+// Token returns the last successfully read token.
+func (s *FileTokenSource) Token() (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.token, nil
+}
+
+// Invalidate re-reads token file if the given token is still the current one.
+func (s *FileTokenSource) Invalidate(token string) {
+	if current, _ := s.Token(); current == token {
+		_ = s.reload()
+	}
+}
+
+// Close stops watching the token file.
+func (s *FileTokenSource) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stopCh)
+		<-s.doneCh
+		if s.watcher != nil {
+			s.watcher.Close()
+		}
+	})
+	return nil
+}
+
+func (s *FileTokenSource) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.opts.refreshInterval)
+	defer ticker.Stop()
+
+	var events <-chan fsnotify.Event
+	var errs <-chan error
+	if s.watcher != nil {
+		events, errs = s.watcher.Events, s.watcher.Errors
+	}
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			_ = s.reload()
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			// Symlink swap changes other entries of the directory, so any event triggers re-read.
+			_ = s.reload()
+		case _, ok := <-errs:
+			if !ok {
+				errs = nil
+			}
+		}
+	}
+}
+
+// reload reads token file. Previous token is kept if file can't be read or is empty,
+// e.g. in the middle of rotation.
+func (s *FileTokenSource) reload() error {
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return fmt.Errorf("token file %q is empty", s.path)
+	}
+	s.mu.Lock()
+	s.token = token
+	s.mu.Unlock()
+	return nil
+}
+
+// contextTokenSource is implemented by token sources which can block, e.g. while running exec plugin,
+// so waiting for token is stopped when request context is done.
+type contextTokenSource interface {
+	tokenContext(ctx context.Context) (string, error)
+}
+
+func requestToken(source TokenSource, req *http.Request) (string, error) {
+	if s, ok := source.(contextTokenSource); ok {
+		return s.tokenContext(req.Context())
+	}
+	return source.Token()
+}
+
+// roundTripWithToken sends request with token from the source. If API server responds with 401, token is
+// invalidated and request is retried once with a new token.
+func roundTripWithToken(httpClient *http.Client, source TokenSource, req *http.Request) (*http.Response, error) {
+	token, err := requestToken(source, req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(withBearerToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	source.Invalidate(token)
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	newToken, err := requestToken(source, req)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if newToken == token {
+		return resp, nil
+	}
+	retry := withBearerToken(req, newToken)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		retry.Body = body
+	}
+	resp.Body.Close()
+	return httpClient.Do(retry)
+}
+
+func withBearerToken(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
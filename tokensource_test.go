@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "github.com/castai/k8s-client-go/types/core/v1"
+	metav1 "github.com/castai/k8s-client-go/types/meta/v1"
+)
+
+func TestFileTokenSourceSymlinkSwap(t *testing.T) {
+	// Reproduce kubelet atomic writer layout: token -> ..data/token, ..data -> ..2023_01_01.
+	dir := t.TempDir()
+	writeTokenDir := func(name, token string) {
+		if err := os.Mkdir(filepath.Join(dir, name), 0700); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, name, "token"), token)
+	}
+	writeTokenDir("..2023_01_01", "token1")
+	if err := os.Symlink("..2023_01_01", filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..data", "token"), filepath.Join(dir, "token")); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewFileTokenSource(filepath.Join(dir, "token"), WithTokenRefreshInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if token, _ := s.Token(); token != "token1" {
+		t.Fatalf("expected token %q, got %q", "token1", token)
+	}
+
+	writeTokenDir("..2023_01_02", "token2")
+	if err := os.Symlink("..2023_01_02", filepath.Join(dir, "..data_tmp")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if token, _ := s.Token(); token == "token2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for rotated token")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-s.doneCh:
+	default:
+		t.Fatal("expected watcher goroutine to be stopped")
+	}
+}
+
+func TestFileTokenSourcePeriodicRefresh(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "token"), "token1")
+	s, err := NewFileTokenSource(filepath.Join(dir, "token"), WithTokenRefreshInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// Stop file notifications to check fallback.
+	s.watcher.Close()
+	writeFile(t, filepath.Join(dir, "token"), "token2")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if token, _ := s.Token(); token == "token2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for periodic token refresh")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestClientRefreshTokenOnUnauthorized(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer token2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		var endpoints corev1.Endpoints
+		if err := json.Unmarshal(body, &endpoints); err != nil {
+			t.Errorf("expected retried request body to be sent: %v", err)
+		}
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	source := &rotatingTokenSource{tokens: []string{"token1", "token2"}}
+	kc, err := NewForConfig(&Config{Host: srv.URL, TokenSource: source})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kc.Close()
+
+	api := NewObjectAPI[corev1.Endpoints](kc)
+	endpoints := &corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "endpoint1", Namespace: "test"}}
+	created, err := api.Create(context.Background(), "test", endpoints, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != "endpoint1" {
+		t.Fatalf("expected created endpoint1, got %q", created.Name)
+	}
+	if kc.Token() != "token2" {
+		t.Fatalf("expected refreshed token %q, got %q", "token2", kc.Token())
+	}
+}
+
+// rotatingTokenSource returns next token after invalidation.
+type rotatingTokenSource struct {
+	mu     sync.Mutex
+	tokens []string
+}
+
+func (s *rotatingTokenSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[0], nil
+}
+
+func (s *rotatingTokenSource) Invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.tokens) > 1 && s.tokens[0] == token {
+		s.tokens = s.tokens[1:]
+	}
+}
@@ -12,13 +12,52 @@ type ObjectGetter[T corev1.Object] interface {
 	Get(ctx context.Context, namespace, name string, _ metav1.GetOptions) (*T, error)
 }
 
+// ObjectLister is generic object lister.
+type ObjectLister[T corev1.Object] interface {
+	List(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.List[T], error)
+}
+
 // ObjectWatcher is generic object watcher.
 type ObjectWatcher[T corev1.Object] interface {
 	Watch(ctx context.Context, namespace, name string, _ metav1.ListOptions) (WatchInterface[T], error)
 }
 
+// ObjectCreator is generic object creator.
+type ObjectCreator[T corev1.Object] interface {
+	Create(ctx context.Context, namespace string, obj *T, opts metav1.CreateOptions) (*T, error)
+}
+
+// ObjectUpdater is generic object updater. Object name is taken from object metadata.
+type ObjectUpdater[T corev1.Object] interface {
+	Update(ctx context.Context, namespace string, obj *T, opts metav1.UpdateOptions) (*T, error)
+}
+
+// ObjectDeleter is generic object deleter.
+type ObjectDeleter[T corev1.Object] interface {
+	Delete(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error
+}
+
+// ObjectPatcher is generic object patcher.
+type ObjectPatcher[T corev1.Object] interface {
+	Patch(ctx context.Context, namespace, name string, pt metav1.PatchType, data []byte, opts metav1.PatchOptions) (*T, error)
+}
+
+// ObjectApplier is generic server-side object applier. Object name and namespace are taken from object metadata.
+// Struct fields with zero values are not sent, as typed objects can't tell unset fields from zero ones, so Apply
+// can't set non-pointer field to zero value, e.g. false or 0. Map entries, e.g. labels with empty values, are sent
+// as is. Use Patch with metav1.ApplyPatchType and partial object to set zero values.
+type ObjectApplier[T corev1.Object] interface {
+	Apply(ctx context.Context, obj *T, opts metav1.ApplyOptions) (*T, error)
+}
+
 // ObjectAPI wraps all operations on object.
 type ObjectAPI[T corev1.Object] interface {
 	ObjectGetter[T]
+	ObjectLister[T]
 	ObjectWatcher[T]
+	ObjectCreator[T]
+	ObjectUpdater[T]
+	ObjectDeleter[T]
+	ObjectPatcher[T]
+	ObjectApplier[T]
 }
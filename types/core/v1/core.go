@@ -11,12 +11,24 @@ const (
 	EventTypeModified EventType = "MODIFIED"
 	EventTypeDeleted  EventType = "DELETED"
 	EventTypeError    EventType = "ERROR"
+	// EventTypeBookmark is sent by API server when watch bookmarks are allowed. Bookmark object
+	// has only resource version set and marks that all changes up to it were sent.
+	EventTypeBookmark EventType = "BOOKMARK"
 )
 
 // Event represents a single event to a watched resource.
 type Event[T Object] struct {
 	Type   EventType `json:"type"`
 	Object *T        `json:"object"`
+	// Status is set instead of Object for events of type ERROR.
+	Status *metav1.Status `json:"-"`
+}
+
+// List is a generic list of kubernetes objects as returned by list calls.
+type List[T Object] struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []T `json:"items"`
 }
 
 // Object is kubernetes object.
@@ -0,0 +1,51 @@
+package v1
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RFC3339Micro is the format used by API server to serialize MicroTime.
+const RFC3339Micro = "2006-01-02T15:04:05.000000Z07:00"
+
+// MicroTime is version of time.Time which is serialized with microsecond precision.
+// Zero value is serialized as null.
+type MicroTime struct {
+	time.Time `protobuf:"-"`
+}
+
+// NewMicroTime returns a wrapped instance of the provided time.
+func NewMicroTime(t time.Time) MicroTime {
+	return MicroTime{t}
+}
+
+// NowMicro returns the current local time.
+func NowMicro() MicroTime {
+	return MicroTime{time.Now()}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t MicroTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.UTC().Format(RFC3339Micro))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *MicroTime) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	pt, err := time.Parse(RFC3339Micro, str)
+	if err != nil {
+		return err
+	}
+	t.Time = pt.Local()
+	return nil
+}
@@ -0,0 +1,163 @@
+// Package workqueue implements work queues for controllers. It's adapted from k8s.io/client-go/util/workqueue.
+package workqueue
+
+import (
+	"sync"
+)
+
+// Interface is a work queue. Items are deduplicated: an item added several times before it is
+// processed is processed only once. An item which is being processed is not handed out to another
+// worker, if it is added again while processing, it is re-queued once Done is called.
+type Interface[T comparable] interface {
+	// Add marks item as needing processing.
+	Add(item T)
+	// Len returns number of items waiting for processing.
+	Len() int
+	// Get blocks until it can return an item to be processed. If shutdown is true,
+	// the caller should end their goroutine. You must call Done with item when you
+	// have finished processing it.
+	Get() (item T, shutdown bool)
+	// Done marks item as done processing, and if it has been marked as dirty again
+	// while it was being processed, it will be re-added to the queue for
+	// re-processing.
+	Done(item T)
+	// ShutDown will cause queue to ignore all new items added to it. Workers receive
+	// remaining queued items and then shutdown signal.
+	ShutDown()
+	// ShutDownWithDrain is like ShutDown, but it also waits until all items being
+	// processed are marked as done.
+	ShutDownWithDrain()
+	// ShuttingDown returns true if queue is shutting down.
+	ShuttingDown() bool
+}
+
+// New constructs a new work queue.
+func New[T comparable]() *Type[T] {
+	return &Type[T]{
+		dirty:      set[T]{},
+		processing: set[T]{},
+		cond:       sync.NewCond(&sync.Mutex{}),
+	}
+}
+
+// Type is a work queue.
+type Type[T comparable] struct {
+	// queue defines the order in which we will work on items.
+	queue []T
+	// dirty defines all of the items that need to be processed.
+	dirty set[T]
+	// processing contains things that are currently being processed.
+	processing set[T]
+
+	cond *sync.Cond
+
+	shuttingDown bool
+	drain        bool
+}
+
+type set[T comparable] map[T]struct{}
+
+func (s set[T]) has(item T) bool {
+	_, exists := s[item]
+	return exists
+}
+
+func (s set[T]) insert(item T) {
+	s[item] = struct{}{}
+}
+
+func (s set[T]) delete(item T) {
+	delete(s, item)
+}
+
+// Add marks item as needing processing.
+func (q *Type[T]) Add(item T) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	if q.dirty.has(item) {
+		return
+	}
+	q.dirty.insert(item)
+	if q.processing.has(item) {
+		return
+	}
+	q.queue = append(q.queue, item)
+	q.cond.Signal()
+}
+
+// Len returns the current queue length, for informational purposes only. You
+// shouldn't e.g. gate a call to Add() or Get() on Len() being a particular
+// value, that can't be synchronized properly.
+func (q *Type[T]) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return len(q.queue)
+}
+
+// Get blocks until it can return an item to be processed.
+func (q *Type[T]) Get() (T, bool) {
+	var zero T
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for len(q.queue) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if len(q.queue) == 0 {
+		// We must be shutting down.
+		return zero, true
+	}
+	item := q.queue[0]
+	q.queue[0] = zero
+	q.queue = q.queue[1:]
+
+	q.processing.insert(item)
+	q.dirty.delete(item)
+	return item, false
+}
+
+// Done marks item as done processing.
+func (q *Type[T]) Done(item T) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.processing.delete(item)
+	if q.dirty.has(item) {
+		q.queue = append(q.queue, item)
+		q.cond.Signal()
+	} else if len(q.processing) == 0 {
+		// Wake up ShutDownWithDrain waiting for processing items.
+		q.cond.Broadcast()
+	}
+}
+
+// ShutDown will cause q to ignore all new items added to it.
+func (q *Type[T]) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = false
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+// ShutDownWithDrain will cause q to ignore all new items added to it and waits
+// until all items being processed are done. It blocks forever if workers never
+// call Done for handed out items.
+func (q *Type[T]) ShutDownWithDrain() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = true
+	q.shuttingDown = true
+	q.cond.Broadcast()
+	for len(q.processing) != 0 && q.drain {
+		q.cond.Wait()
+	}
+}
+
+// ShuttingDown returns true if queue is shutting down.
+func (q *Type[T]) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
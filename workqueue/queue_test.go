@@ -0,0 +1,126 @@
+package workqueue
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueueDeduplication(t *testing.T) {
+	q := New[string]()
+	q.Add("a")
+	q.Add("a")
+	q.Add("b")
+	if q.Len() != 2 {
+		t.Fatalf("expected 2 items, got %d", q.Len())
+	}
+
+	item, shutdown := q.Get()
+	if shutdown || item != "a" {
+		t.Fatalf("expected item %q, got %q", "a", item)
+	}
+	// Item added while processing is re-queued only after Done.
+	q.Add("a")
+	if q.Len() != 1 {
+		t.Fatalf("expected 1 item while processing, got %d", q.Len())
+	}
+	q.Done("a")
+	if q.Len() != 2 {
+		t.Fatalf("expected 2 items after done, got %d", q.Len())
+	}
+}
+
+func TestQueueShutDownWithDrain(t *testing.T) {
+	q := New[string]()
+	q.Add("a")
+	item, _ := q.Get()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	drained := make(chan struct{})
+	go func() {
+		defer wg.Done()
+		q.ShutDownWithDrain()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		t.Fatal("expected shutdown to wait for processing item")
+	case <-time.After(50 * time.Millisecond):
+	}
+	q.Done(item)
+	wg.Wait()
+
+	q.Add("b")
+	if _, shutdown := q.Get(); !shutdown {
+		t.Fatal("expected queue to be shut down")
+	}
+}
+
+func TestDelayingQueue(t *testing.T) {
+	q := NewDelayingQueue[string]()
+	defer q.ShutDown()
+
+	q.AddAfter("a", time.Hour)
+	if q.Len() != 0 {
+		t.Fatalf("expected no items before delay, got %d", q.Len())
+	}
+	// Earliest delay wins, otherwise item isn't ready for an hour.
+	q.AddAfter("a", 10*time.Millisecond)
+	if item := getWithTimeout(t, q); item != "a" {
+		t.Fatalf("expected item %q, got %q", "a", item)
+	}
+	q.Done("a")
+}
+
+func TestDelayingQueueInsertDeduplication(t *testing.T) {
+	pq := &waitForPriorityQueue[string]{}
+	known := map[string]*waitFor[string]{}
+	now := time.Now()
+
+	insert(pq, known, &waitFor[string]{data: "a", readyAt: now.Add(time.Hour)})
+	insert(pq, known, &waitFor[string]{data: "a", readyAt: now.Add(time.Minute)})
+	// Later duplicated add is dropped.
+	insert(pq, known, &waitFor[string]{data: "a", readyAt: now.Add(2 * time.Hour)})
+	if pq.Len() != 1 {
+		t.Fatalf("expected 1 waiting entry, got %d", pq.Len())
+	}
+	if readyAt := pq.Peek().readyAt; !readyAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected earliest ready time to win, got %v", readyAt.Sub(now))
+	}
+}
+
+func getWithTimeout(t *testing.T, q Interface[string]) string {
+	t.Helper()
+	res := make(chan string, 1)
+	go func() {
+		item, _ := q.Get()
+		res <- item
+	}()
+	select {
+	case item := <-res:
+		return item
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for item")
+		return ""
+	}
+}
+
+func TestRateLimitingQueue(t *testing.T) {
+	limiter := NewItemExponentialFailureRateLimiter[string](time.Millisecond, time.Second)
+	q := NewRateLimitingQueue[string](limiter)
+	defer q.ShutDown()
+
+	q.AddRateLimited("a")
+	q.AddRateLimited("a")
+	if q.NumRequeues("a") != 2 {
+		t.Fatalf("expected 2 requeues, got %d", q.NumRequeues("a"))
+	}
+	item, _ := q.Get()
+	q.Forget(item)
+	q.Done(item)
+	if q.NumRequeues("a") != 0 {
+		t.Fatalf("expected requeues to be forgotten, got %d", q.NumRequeues("a"))
+	}
+}
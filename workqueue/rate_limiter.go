@@ -0,0 +1,176 @@
+package workqueue
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter decides how long an item should wait before it is processed again.
+type RateLimiter[T comparable] interface {
+	// When gets an item and gets to decide how long that item should wait.
+	When(item T) time.Duration
+	// Forget indicates that an item is finished being retried. Doesn't matter whether it's for failing
+	// or for success, we'll stop tracking it.
+	Forget(item T)
+	// NumRequeues returns back how many failures the item has had.
+	NumRequeues(item T) int
+}
+
+// DefaultControllerRateLimiter is a no-arg constructor for a default rate limiter for a workqueue. It has
+// both overall and per-item rate limiting. The overall is a token bucket and the per-item is exponential.
+func DefaultControllerRateLimiter[T comparable]() RateLimiter[T] {
+	return NewMaxOfRateLimiter[T](
+		NewItemExponentialFailureRateLimiter[T](5*time.Millisecond, 1000*time.Second),
+		// 10 qps, 100 bucket size. This is only for retry speed and its only the overall factor (not per item).
+		NewBucketRateLimiter[T](10, 100),
+	)
+}
+
+// BucketRateLimiter adapts a standard token bucket to the workqueue ratelimiter API. Tokens are
+// refilled at qps rate up to burst size. Each When call takes a token and returns the time left
+// until the token is available.
+type BucketRateLimiter[T comparable] struct {
+	qps   float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+var _ RateLimiter[string] = &BucketRateLimiter[string]{}
+
+// NewBucketRateLimiter creates token bucket limiter with full bucket.
+func NewBucketRateLimiter[T comparable](qps float64, burst int) *BucketRateLimiter[T] {
+	return &BucketRateLimiter[T]{
+		qps:    qps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (r *BucketRateLimiter[T]) When(_ T) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Seconds() * r.qps
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+	r.last = now
+
+	// Negative tokens are reservations which will be fulfilled in the future.
+	r.tokens--
+	if r.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-r.tokens / r.qps * float64(time.Second))
+}
+
+func (r *BucketRateLimiter[T]) NumRequeues(_ T) int {
+	return 0
+}
+
+func (r *BucketRateLimiter[T]) Forget(_ T) {
+}
+
+// ItemExponentialFailureRateLimiter does a simple baseDelay*2^<num-failures> limit
+// dealing with max failures and expiration are up to the caller.
+type ItemExponentialFailureRateLimiter[T comparable] struct {
+	failuresLock sync.Mutex
+	failures     map[T]int
+
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+var _ RateLimiter[string] = &ItemExponentialFailureRateLimiter[string]{}
+
+// NewItemExponentialFailureRateLimiter creates per item exponential backoff limiter.
+func NewItemExponentialFailureRateLimiter[T comparable](baseDelay time.Duration, maxDelay time.Duration) *ItemExponentialFailureRateLimiter[T] {
+	return &ItemExponentialFailureRateLimiter[T]{
+		failures:  map[T]int{},
+		baseDelay: baseDelay,
+		maxDelay:  maxDelay,
+	}
+}
+
+func (r *ItemExponentialFailureRateLimiter[T]) When(item T) time.Duration {
+	r.failuresLock.Lock()
+	defer r.failuresLock.Unlock()
+
+	exp := r.failures[item]
+	r.failures[item] = r.failures[item] + 1
+
+	// The backoff is capped such that 'calculated' value never overflows.
+	backoff := float64(r.baseDelay.Nanoseconds()) * math.Pow(2, float64(exp))
+	if backoff > math.MaxInt64 {
+		return r.maxDelay
+	}
+
+	calculated := time.Duration(backoff)
+	if calculated > r.maxDelay {
+		return r.maxDelay
+	}
+
+	return calculated
+}
+
+func (r *ItemExponentialFailureRateLimiter[T]) NumRequeues(item T) int {
+	r.failuresLock.Lock()
+	defer r.failuresLock.Unlock()
+
+	return r.failures[item]
+}
+
+func (r *ItemExponentialFailureRateLimiter[T]) Forget(item T) {
+	r.failuresLock.Lock()
+	defer r.failuresLock.Unlock()
+
+	delete(r.failures, item)
+}
+
+// MaxOfRateLimiter calls every RateLimiter and returns the worst case response.
+// When used with a token bucket limiter, the burst could be apparently exceeded in cases where particular items
+// were separately delayed a longer time.
+type MaxOfRateLimiter[T comparable] struct {
+	limiters []RateLimiter[T]
+}
+
+// NewMaxOfRateLimiter creates limiter which returns max delay of all limiters.
+func NewMaxOfRateLimiter[T comparable](limiters ...RateLimiter[T]) *MaxOfRateLimiter[T] {
+	return &MaxOfRateLimiter[T]{limiters: limiters}
+}
+
+func (r *MaxOfRateLimiter[T]) When(item T) time.Duration {
+	ret := time.Duration(0)
+	for _, limiter := range r.limiters {
+		curr := limiter.When(item)
+		if curr > ret {
+			ret = curr
+		}
+	}
+
+	return ret
+}
+
+func (r *MaxOfRateLimiter[T]) NumRequeues(item T) int {
+	ret := 0
+	for _, limiter := range r.limiters {
+		curr := limiter.NumRequeues(item)
+		if curr > ret {
+			ret = curr
+		}
+	}
+
+	return ret
+}
+
+func (r *MaxOfRateLimiter[T]) Forget(item T) {
+	for _, limiter := range r.limiters {
+		limiter.Forget(item)
+	}
+}
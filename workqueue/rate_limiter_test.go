@@ -0,0 +1,53 @@
+package workqueue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestItemExponentialFailureRateLimiter(t *testing.T) {
+	limiter := NewItemExponentialFailureRateLimiter[string](time.Millisecond, 4*time.Millisecond)
+
+	for i, expected := range []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond} {
+		if d := limiter.When("a"); d != expected {
+			t.Fatalf("attempt %d: expected delay %v, got %v", i, expected, d)
+		}
+	}
+	if d := limiter.When("b"); d != time.Millisecond {
+		t.Fatalf("expected independent delay for other item, got %v", d)
+	}
+	limiter.Forget("a")
+	if d := limiter.When("a"); d != time.Millisecond {
+		t.Fatalf("expected reset delay after forget, got %v", d)
+	}
+}
+
+func TestBucketRateLimiter(t *testing.T) {
+	limiter := NewBucketRateLimiter[string](1, 2)
+
+	if d := limiter.When("a"); d != 0 {
+		t.Fatalf("expected no delay for burst, got %v", d)
+	}
+	if d := limiter.When("b"); d != 0 {
+		t.Fatalf("expected no delay for burst, got %v", d)
+	}
+	if d := limiter.When("c"); d < 900*time.Millisecond || d > time.Second {
+		t.Fatalf("expected about one second delay after burst, got %v", d)
+	}
+	if d := limiter.When("d"); d < 1900*time.Millisecond || d > 2*time.Second {
+		t.Fatalf("expected about two second delay for next reservation, got %v", d)
+	}
+}
+
+func TestMaxOfRateLimiter(t *testing.T) {
+	limiter := NewMaxOfRateLimiter[string](
+		NewItemExponentialFailureRateLimiter[string](time.Millisecond, time.Second),
+		NewItemExponentialFailureRateLimiter[string](10*time.Millisecond, time.Second),
+	)
+	if d := limiter.When("a"); d != 10*time.Millisecond {
+		t.Fatalf("expected max delay, got %v", d)
+	}
+	if n := limiter.NumRequeues("a"); n != 1 {
+		t.Fatalf("expected 1 requeue, got %d", n)
+	}
+}
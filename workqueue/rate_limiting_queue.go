@@ -0,0 +1,46 @@
+package workqueue
+
+// RateLimitingInterface is an interface that rate limits items being added to the queue.
+type RateLimitingInterface[T comparable] interface {
+	DelayingInterface[T]
+
+	// AddRateLimited adds an item to the workqueue after the rate limiter says it's ok.
+	AddRateLimited(item T)
+
+	// Forget indicates that an item is finished being retried. Doesn't matter whether it's for perm failing
+	// or for success, we'll stop the rate limiter from tracking it. This only clears the `rateLimiter`, you
+	// still have to call `Done` on the queue.
+	Forget(item T)
+
+	// NumRequeues returns back how many times the item was requeued.
+	NumRequeues(item T) int
+}
+
+// NewRateLimitingQueue constructs a new workqueue with rateLimited queuing ability.
+// Remember to call Forget! If you don't, you may end up tracking failures forever.
+func NewRateLimitingQueue[T comparable](rateLimiter RateLimiter[T]) RateLimitingInterface[T] {
+	return &rateLimitingType[T]{
+		DelayingInterface: NewDelayingQueue[T](),
+		rateLimiter:       rateLimiter,
+	}
+}
+
+// rateLimitingType wraps an Interface and provides rateLimited re-enquing.
+type rateLimitingType[T comparable] struct {
+	DelayingInterface[T]
+
+	rateLimiter RateLimiter[T]
+}
+
+// AddRateLimited AddAfter's the item based on the time when the rate limiter says it's ok.
+func (q *rateLimitingType[T]) AddRateLimited(item T) {
+	q.DelayingInterface.AddAfter(item, q.rateLimiter.When(item))
+}
+
+func (q *rateLimitingType[T]) NumRequeues(item T) int {
+	return q.rateLimiter.NumRequeues(item)
+}
+
+func (q *rateLimitingType[T]) Forget(item T) {
+	q.rateLimiter.Forget(item)
+}